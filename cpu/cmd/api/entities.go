@@ -17,11 +17,23 @@ type Config struct {
 	CacheReplacement string        `json:"cache_replacement"`
 	CacheDelay       time.Duration `json:"cache_delay"`
 	LogLevel         string        `json:"log_level"`
+	ClockMode        string        `json:"clock_mode"`
+	InstructionTime  int           `json:"instruction_time"` // Milisegundos que dura cada instrucción con el reloj virtual
+}
+
+// DuracionInstruccionPorDefecto son los milisegundos que dura una instrucción con el reloj virtual si no se configura
+// instruction_time
+const DuracionInstruccionPorDefecto = 10
+
+// respuestaConexion es lo que responde el kernel a la identificación de la CPU
+type respuestaConexion struct {
+	ClockMode string `json:"clock_mode"` // La CPU usa el mismo modo de reloj que el kernel
 }
 
 type Proceso struct {
-	PID int `json:"pid"`
-	PC  int `json:"pc"`
+	PID     int `json:"pid"`
+	PC      int `json:"pc"`
+	Tamanio int `json:"tamanio"` // Tamaño del proceso en bytes, límite de sus direcciones lógicas
 }
 
 type Instruccion struct {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/sisoputnfrba/tp-golang/cpu/internal"
 	"github.com/sisoputnfrba/tp-golang/cpu/pkg/memoria"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/config"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
@@ -33,6 +34,12 @@ func NewHandler(configFile string) *Handler {
 
 	// Initialize the logger with the log level from the configuration
 	logLevel := configStruct.LogLevel
+
+	// Initialize the clock (real or virtual) with the mode from the configuration
+	clock.Configurar(configStruct.ClockMode)
+	if configStruct.InstructionTime <= 0 {
+		configStruct.InstructionTime = DuracionInstruccionPorDefecto
+	}
 	logger := log.BuildLogger(logLevel)
 
 	mem := memoria.NewMemoria(configStruct.IpMemory, configStruct.PortMemory, logger)
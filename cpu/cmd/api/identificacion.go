@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -34,10 +36,21 @@ func (h *Handler) EnviarIdentificacion(nombre string) {
 	}
 
 	if resp != nil {
+		defer resp.Body.Close()
 		h.Log.Debug("Respuesta del servidor",
 			log.StringAttr("status", resp.Status),
 			log.StringAttr("body", string(body)),
 		)
+
+		// Se usa el modo de reloj del kernel, para que las ráfagas que se le informan estén en su mismo tiempo
+		var respuesta respuestaConexion
+		if err := json.NewDecoder(resp.Body).Decode(&respuesta); err == nil && respuesta.ClockMode != "" &&
+			strings.EqualFold(respuesta.ClockMode, clock.ModoVirtual) != clock.EsVirtual() {
+			clock.Configurar(respuesta.ClockMode)
+			h.Log.Info("Se usa el modo de reloj del kernel",
+				log.StringAttr("clock_mode", respuesta.ClockMode),
+			)
+		}
 	} else {
 		h.Log.Debug("Respuesta del servidor: nil")
 	}
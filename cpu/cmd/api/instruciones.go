@@ -1,11 +1,17 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sisoputnfrba/tp-golang/cpu/internal"
+	"github.com/sisoputnfrba/tp-golang/cpu/pkg/kernel"
+	"github.com/sisoputnfrba/tp-golang/cpu/pkg/memoria"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/contexto"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -29,12 +35,78 @@ func (h *Handler) Fetch(pid int, pc int) (Instruccion, error) {
 	return response, nil
 }
 
-// Decode Interpreta la instrucción y sus argumentos. Además, verifica si la misma requiere de una
-// traducción de dirección lógica a física.
+// aridad es la cantidad mínima y máxima de argumentos de una instrucción
+type aridad struct {
+	min, max int
+}
+
+// instruccionesValidas son las instrucciones que entiende la CPU, con la cantidad de argumentos que aceptan
+var instruccionesValidas = map[string]aridad{
+	"NOOP":        {0, 0},
+	"WRITE":       {2, 2},
+	"READ":        {2, 2},
+	"GOTO":        {1, 1},
+	"IO":          {2, 2},
+	"INIT_PROC":   {2, 4}, // archivo, tamaño y opcionalmente prioridad y tickets
+	"DUMP_MEMORY": {0, 0},
+	"WAIT":        {1, 1}, // Recurso, PID de un hijo o ANY
+	"SIGNAL":      {1, 1}, // Recurso
+	"SEND":        {2, 2}, // PID o mailbox destino y mensaje
+	"RECV":        {2, 2}, // Mailbox y dirección donde se escribe el mensaje
+	"EXIT":        {0, 0},
+}
+
+// argumentosNumericos indica qué argumentos de cada instrucción deben ser números enteros
+var argumentosNumericos = map[string][]int{
+	"WRITE":     {0},    // dirección
+	"READ":      {0, 1}, // dirección y tamaño
+	"GOTO":      {0},    // PC destino
+	"IO":        {1},    // tiempo
+	"INIT_PROC": {1},    // tamaño
+	"RECV":      {1},    // dirección
+}
+
+// excepcionInstruccion es un error de la instrucción que se informa al kernel como excepción
+type excepcionInstruccion struct {
+	codigo  string
+	mensaje string
+}
+
+func (e *excepcionInstruccion) Error() string {
+	return fmt.Sprintf("%s: %s", e.codigo, e.mensaje)
+}
+
+// Decode Interpreta la instrucción y sus argumentos. Verifica que la instrucción exista, que tenga la cantidad de
+// argumentos correcta y que los argumentos numéricos lo sean; si no, devuelve una excepcionInstruccion.
 func (h *Handler) decode(instruccion Instruccion) (string, []string, error) {
 	tipo := strings.ToUpper(instruccion.Instruccion)
 	args := instruccion.Parametros
 
+	cantidad, ok := instruccionesValidas[tipo]
+	if !ok {
+		return tipo, args, &excepcionInstruccion{
+			codigo:  contexto.CodigoInstruccionInvalida,
+			mensaje: fmt.Sprintf("instrucción desconocida %q", instruccion.Instruccion),
+		}
+	}
+
+	if len(args) < cantidad.min || len(args) > cantidad.max {
+		return tipo, args, &excepcionInstruccion{
+			codigo: contexto.CodigoCantidadArgumentos,
+			mensaje: fmt.Sprintf("%s recibe entre %d y %d argumentos, se recibieron %d",
+				tipo, cantidad.min, cantidad.max, len(args)),
+		}
+	}
+
+	for _, i := range argumentosNumericos[tipo] {
+		if _, err := strconv.Atoi(args[i]); err != nil {
+			return tipo, args, &excepcionInstruccion{
+				codigo:  contexto.CodigoArgumentoInvalido,
+				mensaje: fmt.Sprintf("el argumento %d de %s debe ser numérico: %q", i+1, tipo, args[i]),
+			}
+		}
+	}
+
 	// Para READ y WRITE, no traducimos aquí - lo harán las funciones LeerConCache y EscribirConCache
 	// que necesitan la dirección lógica original para calcular el número de página correctamente
 
@@ -43,28 +115,22 @@ func (h *Handler) decode(instruccion Instruccion) (string, []string, error) {
 
 // Execute ejecuta la instrucción decodificada. Dependiendo del tipo de instrucción, puede
 // requerir interacción con la memoria, el kernel o simplemente ser una operación no operativa (NOOP).
-func (h *Handler) Execute(tipo string, args []string, pid, pc int) (bool, int) {
-	var (
-		nuevoPC       = pc
-		returnControl bool // Retornamos false para indicar que el CPU debe devolver el control al kernel
-	)
+// Devuelve el nuevo PC y, si el proceso debe devolver la CPU, el contexto con el motivo. Las syscalls bloqueantes
+// (IO, DUMP_MEMORY, WAIT de un hijo, RECV) no se envían acá: viajan al kernel junto con el contexto del proceso.
+// IMPORTANTE: La instrucción ya fue validada por decode
+func (h *Handler) Execute(tipo string, args []string, pid, pc int) (int, *contexto.Contexto) {
+	nuevoPC := pc
 
 	// Log obligatorio: Instrucción Ejecutada
 	//“## PID: <PID> - Ejecutando: <INSTRUCCION> - <PARAMETROS>”.
 	h.Log.Info(fmt.Sprintf("## PID: %d - Ejecutando: %s - %s", pid, tipo, strings.Join(args, " ")))
+	internal.MetricaInstrucciones.Inc(tipo)
 
 	switch tipo {
 	case "NOOP":
 		nuevoPC++
-		returnControl = true
 
 	case "WRITE":
-		if len(args) < 2 {
-			h.Log.Error("WRITE requiere al menos 2 argumentos: dirección y datos",
-				log.IntAttr("pid", pid),
-				log.IntAttr("pc", pc))
-			return false, pc
-		}
 		direccionLogica := args[0] // Dirección lógica
 		datos := args[1]
 
@@ -75,62 +141,30 @@ func (h *Handler) Execute(tipo string, args []string, pid, pc int) (bool, int) {
 				log.IntAttr("pid", pid),
 				log.StringAttr("direccion", direccionLogica),
 				log.StringAttr("datos", datos))
-			return false, pc
+			return pc, h.falloMemoria(pid, err, tipo, args, direccionLogica)
 		}
 
 		nuevoPC++
-		returnControl = true
 
 	case "READ":
-		if len(args) < 2 {
-			h.Log.Error("READ requiere al menos 2 argumentos: dirección y tamaño",
-				log.IntAttr("pid", pid),
-				log.IntAttr("pc", pc))
-			return false, pc
-		}
-
 		direccionLogica := args[0] // Dirección lógica
-		tamanio, err := strconv.Atoi(args[1])
-		if err != nil {
-			h.Log.Error("Tamaño inválido en instrucción READ",
-				log.ErrAttr(err),
-				log.IntAttr("pid", pid),
-				log.StringAttr("tamanio_str", args[1]))
-			return false, pc
-		}
+		tamanio, _ := strconv.Atoi(args[1])
 
 		// Usar la MMU para leer con caché. Si la caché no está habilitada, se lee directamente en memoria
-		if err = h.Service.MMU.LeerConCache(pid, direccionLogica, tamanio); err != nil {
+		if err := h.Service.MMU.LeerConCache(pid, direccionLogica, tamanio); err != nil {
 			h.Log.Error("Error al leer de memoria",
 				log.ErrAttr(err),
 				log.IntAttr("pid", pid),
 				log.StringAttr("direccion", direccionLogica),
 				log.IntAttr("tamanio", tamanio))
-			return false, pc
+			return pc, h.falloMemoria(pid, err, tipo, args, direccionLogica)
 		}
 
 		nuevoPC++
-		returnControl = true
 
 	case "GOTO":
-		if len(args) != 1 {
-			h.Log.Error("GOTO requiere un único argumento numérico",
-				log.IntAttr("pid", pid),
-				log.IntAttr("pc", pc),
-				log.AnyAttr("args", args))
-			return false, pc
-		}
-
-		pcAtoi, err := strconv.Atoi(args[0])
-		if err != nil {
-			h.Log.Error("GOTO requiere un argumento numérico válido",
-				log.ErrAttr(err),
-				log.IntAttr("pid", pid),
-				log.StringAttr("argumento", args[0]))
-			return false, pc
-		}
-		nuevoPC = pcAtoi
-		returnControl = true
+		// Si el destino está fuera del script, el próximo fetch levanta la excepción
+		nuevoPC, _ = strconv.Atoi(args[0])
 
 	case "INIT_PROC":
 		syscall := &internal.ProcesoSyscall{
@@ -140,9 +174,15 @@ func (h *Handler) Execute(tipo string, args []string, pid, pc int) (bool, int) {
 			Args:        args,
 		}
 
-		if err := h.Service.EnviarProcesoSyscall(syscall); err != nil {
+		err := h.Service.EnviarProcesoSyscall(syscall)
+		if errors.Is(err, kernel.ErrSyscallRechazada) {
+			return pc, h.syscallRechazada(pid, tipo, args, err)
+		}
+		if err != nil {
 			h.Log.Error("Error al enviar proceso syscall", log.ErrAttr(err))
-			return false, pc // Si hay error, no avanzamos el PC
+			// Si hay error, no avanzamos el PC: el kernel devuelve el proceso a READY y la syscall se reintenta
+			// cuando vuelva a ejecutar
+			return pc, &contexto.Contexto{Motivo: contexto.MotivoSyscallFallida}
 		}
 
 		h.Log.Debug("Syscall enviada al kernel",
@@ -150,105 +190,182 @@ func (h *Handler) Execute(tipo string, args []string, pid, pc int) (bool, int) {
 			log.StringAttr("instruccion", tipo),
 			log.IntAttr("pc_nuevo", pc+1))
 
-		// Para syscalls, retornamos false para indicar que el CPU debe devolver el control al kernel (menos INIT_PROC)
-		returnControl = true
+		// INIT_PROC no bloquea: el proceso sigue ejecutando
 		nuevoPC++ // Avanzamos el PC para la syscall
 
-	case "IO", "DUMP_MEMORY":
+	case "WAIT", "SIGNAL", "SEND":
+		// WAIT <pid|ANY> espera a que finalice un hijo, así que siempre bloquea
+		if tipo == "WAIT" && esperaAUnHijo(args[0]) {
+			return pc + 1, syscallBloqueante(tipo, args, false)
+		}
+
+		// Los semáforos y los mensajes se atienden mientras el proceso sigue ejecutando. Si el kernel no puede hacerlo
+		// sin bloquearlo (sin instancias, mailbox lleno), se le devuelve el contexto con la syscall para que lo bloquee
 		syscall := &internal.ProcesoSyscall{
 			PID:         pid,
-			PC:          pc + 1, // Avanzamos el PC para la syscall
+			PC:          pc + 1,
 			Instruccion: tipo,
 			Args:        args,
 		}
-
-		if err := h.Service.EnviarProcesoSyscall(syscall); err != nil {
+		err := h.Service.EnviarProcesoSyscall(syscall)
+		if errors.Is(err, kernel.ErrRecursoNoDisponible) {
+			h.Log.Debug("El kernel no atendió la syscall sin bloquear, se devuelve el contexto",
+				log.IntAttr("pid", pid),
+				log.StringAttr("instruccion", tipo),
+				log.StringAttr("recurso", args[0]))
+			return pc + 1, syscallBloqueante(tipo, args, true)
+		}
+		if errors.Is(err, kernel.ErrSyscallRechazada) {
+			return pc, h.syscallRechazada(pid, tipo, args, err)
+		}
+		if err != nil {
 			h.Log.Error("Error al enviar proceso syscall", log.ErrAttr(err))
-			return false, pc // Si hay error, no avanzamos el PC
+			// Si hay error, no avanzamos el PC: el kernel devuelve el proceso a READY y la syscall se reintenta
+			// cuando vuelva a ejecutar
+			return pc, &contexto.Contexto{Motivo: contexto.MotivoSyscallFallida}
 		}
 
-		h.Log.Debug("Syscall enviada al kernel",
+		nuevoPC++
+
+	case "RECV":
+		// El kernel escribe el mensaje directo en memoria: primero se bajan las páginas modificadas de la caché
+		h.Service.LimpiarMemoriaProceso(pid)
+
+		h.Log.Debug("Syscall bloqueante, se devuelve el contexto al kernel",
 			log.IntAttr("pid", pid),
 			log.StringAttr("instruccion", tipo),
 			log.IntAttr("pc_nuevo", pc+1))
 
-		// Para syscalls, retornamos false para indicar que el CPU debe devolver el control al kernel
-		returnControl = false
-		nuevoPC++ // Avanzamos el PC para la syscall
-	case "EXIT":
-		syscall := &internal.ProcesoSyscall{
-			PID:         pid,
-			PC:          pc + 1, // Avanzamos el PC para la syscall
-			Instruccion: tipo,
-			Args:        args,
-		}
+		return pc + 1, syscallBloqueante(tipo, args, false)
+
+	case "IO", "DUMP_MEMORY":
+		h.Log.Debug("Syscall bloqueante, se devuelve el contexto al kernel",
+			log.IntAttr("pid", pid),
+			log.StringAttr("instruccion", tipo),
+			log.IntAttr("pc_nuevo", pc+1))
+
+		// Avanzamos el PC para la syscall y devolvemos el control al kernel
+		return pc + 1, syscallBloqueante(tipo, args, false)
 
+	case "EXIT":
 		// Limpiar memoria (TLB y caché) cuando el proceso termina
 		h.Service.LimpiarMemoriaProceso(pid)
 
-		if err := h.Service.EnviarProcesoSyscall(syscall); err != nil {
-			h.Log.Error("Error al enviar proceso syscall", log.ErrAttr(err))
-			return false, pc // Si hay error, no avanzamos el PC
-		}
+		return pc + 1, &contexto.Contexto{Motivo: contexto.MotivoExit}
+	}
 
-		h.Log.Debug("Syscall enviada al kernel",
-			log.IntAttr("pid", pid),
-			log.StringAttr("instruccion", tipo),
-			log.IntAttr("pc_nuevo", pc+1))
+	return nuevoPC, nil
+}
 
-		// Para syscalls, retornamos false para indicar que el CPU debe devolver el control al kernel
-		returnControl = false
-		nuevoPC++ // Avanzamos el PC para la syscall
+// syscallBloqueante arma el contexto de una ráfaga que terminó por una syscall que atiende el kernel
+func syscallBloqueante(instruccion string, args []string, reintento bool) *contexto.Contexto {
+	return &contexto.Contexto{
+		Motivo:  contexto.MotivoSyscallBloqueante,
+		Syscall: &contexto.Syscall{Instruccion: instruccion, Args: args, Reintento: reintento},
+	}
+}
+
+// syscallRechazada levanta una excepción para el proceso cuya syscall rechazó el kernel: no puede seguir ejecutando
+// como si se hubiera atendido, y reintentarla daría el mismo resultado
+func (h *Handler) syscallRechazada(pid int, instruccion string, args []string, err error) *contexto.Contexto {
+	h.Log.Error("El kernel rechazó la syscall",
+		log.ErrAttr(err),
+		log.IntAttr("pid", pid),
+		log.StringAttr("instruccion", instruccion))
+	h.levantarExcepcion(pid, contexto.MotivoInstruccionInvalida, &contexto.Error{
+		Codigo:      contexto.CodigoSyscallRechazada,
+		Mensaje:     err.Error(),
+		Instruccion: instruccion,
+		Args:        args,
+	})
+
+	return nil
+}
 
-	default:
-		h.Log.Debug("Instrucción no reconocida", log.StringAttr("tipo", tipo))
-		return returnControl, nuevoPC
+// esperaAUnHijo indica si el argumento de WAIT es el PID de un hijo o ANY, en lugar del nombre de un recurso
+func esperaAUnHijo(argumento string) bool {
+	if strings.EqualFold(argumento, "ANY") {
+		return true
 	}
+	_, err := strconv.Atoi(argumento)
+	return err == nil
+}
 
-	return returnControl, nuevoPC
+// contextoError arma el contexto de una ráfaga que terminó por un error de ejecución
+func contextoError(motivo contexto.Motivo, mensaje, instruccion string, args []string, direccion string) *contexto.Contexto {
+	return &contexto.Contexto{
+		Motivo: motivo,
+		Error: &contexto.Error{
+			Mensaje:     mensaje,
+			Instruccion: instruccion,
+			Args:        args,
+			Direccion:   direccion,
+		},
+	}
 }
 
-// Ciclo ejecuta un ciclo de instrucciones para un proceso dado. Su retorno es un mensaje de error
-// si ocurre algún problema.
-func (h *Handler) Ciclo(proceso *Proceso) string {
+// falloMemoria arma el contexto de un acceso a memoria fallido. Si la dirección está fuera del proceso, en lugar de
+// terminar la ráfaga se levanta una excepción, que se atiende al final del ciclo de instrucción.
+func (h *Handler) falloMemoria(pid int, err error, instruccion string, args []string, direccion string) *contexto.Contexto {
+	ctx := contextoError(contexto.MotivoFalloMemoria, err.Error(), instruccion, args, direccion)
+	if !errors.Is(err, internal.ErrSegmentationFault) {
+		return ctx
+	}
+
+	ctx.Error.Codigo = contexto.CodigoSegmentationFault
+	h.levantarExcepcion(pid, ctx.Motivo, ctx.Error)
+
+	return nil
+}
+
+// levantarExcepcion agrega una interrupción de tipo Excepcion para el proceso. Al atenderla, la CPU devuelve el
+// proceso al kernel con el motivo y el detalle dados.
+func (h *Handler) levantarExcepcion(pid int, motivo contexto.Motivo, detalle *contexto.Error) {
+	h.Log.Debug("Excepción levantada",
+		log.IntAttr("pid", pid),
+		log.StringAttr("motivo", string(motivo)),
+		log.StringAttr("codigo", detalle.Codigo))
+
+	h.Service.AgregarInterrupcion(internal.Interrupcion{
+		PID:    pid,
+		Tipo:   internal.InterrupcionExcepcion,
+		Motivo: motivo,
+		Error:  detalle,
+	})
+}
+
+// Ciclo ejecuta un ciclo de instrucciones para un proceso dado, hasta que deba devolver la CPU. Su retorno es el
+// contexto que se devuelve al kernel, con el motivo y el detalle del error si ocurre algún problema.
+func (h *Handler) Ciclo(proceso *Proceso) *contexto.Contexto {
+	inicio := clock.Now()
 	for {
 		h.Log.Debug("Iniciando ciclo de instrucción",
 			log.IntAttr("pid", proceso.PID),
 			log.IntAttr("pc", proceso.PC),
 		)
 
-		instruccion, err := h.Fetch(proceso.PID, proceso.PC)
-		if err != nil {
-			h.Log.Error("Error en fetch", log.ErrAttr(err))
-			return fmt.Sprintf("Error en fetch: %v", err)
-		}
+		fin := h.cicloInstruccion(proceso)
 
-		tipo, args, err := h.decode(instruccion)
-		if err != nil {
-			h.Log.Error("Error en decodificación", log.ErrAttr(err))
-			return fmt.Sprintf("Error en decodificación: %v", err)
+		// Con el reloj virtual el tiempo solo pasa si alguien lo adelanta: cada instrucción dura instruction_time
+		if clock.EsVirtual() {
+			clock.Sleep(time.Duration(h.Config.InstructionTime) * time.Millisecond)
 		}
-		h.Log.Debug("Instrucción decodificada",
-			log.StringAttr("tipo", tipo),
-			log.AnyAttr("args", args))
-
-		// Ejecutar instrucción
-		continuar, nuevoPC := h.Execute(tipo, args, proceso.PID, proceso.PC)
-		proceso.PC = nuevoPC
 
-		// Si la instrucción es EXIT, finalizamos el proceso
-		if tipo == "EXIT" {
-			h.Log.Debug("Proceso finalizado",
-				log.IntAttr("pid", proceso.PID))
-
-			break
+		if fin != nil {
+			h.Log.Debug("Ráfaga terminada por la instrucción",
+				log.IntAttr("pid", proceso.PID),
+				log.StringAttr("motivo", string(fin.Motivo)))
+			return h.completarContexto(proceso, fin, inicio)
 		}
 
-		// Si no se debe continuar (por syscall bloqueante), salir del ciclo
-		if !continuar {
-			h.Log.Debug("Proceso pausado por syscall",
-				log.IntAttr("pid", proceso.PID))
-			break
+		// El kernel adelanta su reloj con lo que lleva la ráfaga, y si vence el quantum la interrupción llega antes
+		// de que se verifiquen las interrupciones
+		if clock.EsVirtual() {
+			if err := h.Service.InformarAvance(proceso.PID, clock.Since(inicio)); err != nil {
+				h.Log.Warn("Error informando el avance de la ráfaga al kernel",
+					log.ErrAttr(err),
+					log.IntAttr("pid", proceso.PID))
+			}
 		}
 
 		// Verificar interrupciones después de cada instrucción
@@ -258,20 +375,86 @@ func (h *Handler) Ciclo(proceso *Proceso) string {
 				log.IntAttr("pc", proceso.PC),
 			)
 
-			// Obtener la interrupción para verificar si es de desalojo
+			// Obtener la interrupción para verificar si es de desalojo o una excepción del proceso
 			interrupcion, found := h.Service.ObtenerInterrupcion(proceso.PID)
+			if found && interrupcion.Tipo == internal.InterrupcionExcepcion && interrupcion.PID == proceso.PID {
+				h.Log.Debug("Excepción detectada, se devuelve el proceso al kernel",
+					log.IntAttr("pid", proceso.PID),
+					log.AnyAttr("error", interrupcion.Error))
+				h.Service.LimpiarMemoriaProceso(proceso.PID)
+
+				return h.completarContexto(proceso, &contexto.Contexto{
+					Motivo: interrupcion.Motivo,
+					Error:  interrupcion.Error,
+				}, inicio)
+			}
 			if found && interrupcion.Tipo == internal.InerrupcionDesalojo && interrupcion.PID == proceso.PID {
 				h.Log.Debug("Interrupción de desalojo detectada, limpiando memoria",
 					log.IntAttr("pid", proceso.PID))
 				h.Service.LimpiarMemoriaProceso(proceso.PID)
 
-				return "Interrupción detectada, proceso pausado"
+				return h.completarContexto(proceso, &contexto.Contexto{Motivo: contexto.MotivoDesalojo}, inicio)
 			}
 		}
 	}
+}
+
+// cicloInstruccion hace fetch, decode y execute de la instrucción apuntada por el PC. Devuelve el contexto si la
+// instrucción termina la ráfaga; los errores de la instrucción se levantan como excepciones.
+func (h *Handler) cicloInstruccion(proceso *Proceso) *contexto.Contexto {
+	instruccion, err := h.Fetch(proceso.PID, proceso.PC)
+	if errors.Is(err, memoria.ErrPCFueraDeRango) {
+		h.levantarExcepcion(proceso.PID, contexto.MotivoErrorFetch, &contexto.Error{
+			Codigo:  contexto.CodigoPCFueraDeRango,
+			Mensaje: err.Error(),
+		})
+		return nil
+	}
+	if err != nil {
+		h.Log.Error("Error en fetch", log.ErrAttr(err))
+		return contextoError(contexto.MotivoErrorFetch, fmt.Sprintf("Error en fetch: %v", err), "", nil, "")
+	}
+
+	tipo, args, err := h.decode(instruccion)
+	var excepcion *excepcionInstruccion
+	if errors.As(err, &excepcion) {
+		h.Log.Error("Instrucción inválida",
+			log.ErrAttr(err),
+			log.IntAttr("pid", proceso.PID),
+			log.IntAttr("pc", proceso.PC))
+		h.levantarExcepcion(proceso.PID, contexto.MotivoInstruccionInvalida, &contexto.Error{
+			Codigo:      excepcion.codigo,
+			Mensaje:     excepcion.mensaje,
+			Instruccion: instruccion.Instruccion,
+			Args:        instruccion.Parametros,
+		})
+		return nil
+	}
+	if err != nil {
+		h.Log.Error("Error en decodificación", log.ErrAttr(err))
+		return contextoError(contexto.MotivoInstruccionInvalida, fmt.Sprintf("Error en decodificación: %v", err),
+			instruccion.Instruccion, instruccion.Parametros, "")
+	}
+	h.Log.Debug("Instrucción decodificada",
+		log.StringAttr("tipo", tipo),
+		log.AnyAttr("args", args))
+
+	// Ejecutar instrucción
+	nuevoPC, fin := h.Execute(tipo, args, proceso.PID, proceso.PC)
+	proceso.PC = nuevoPC
+
+	return fin
+}
+
+// completarContexto agrega al contexto el PID, el PC con el que el proceso devuelve la CPU y lo que duró la ráfaga
+func (h *Handler) completarContexto(proceso *Proceso, ctx *contexto.Contexto, inicio time.Time) *contexto.Contexto {
+	ctx.PID = proceso.PID
+	ctx.PC = proceso.PC
+	ctx.Rafaga = int(clock.Since(inicio).Milliseconds())
 
 	h.Log.Debug("Ciclo de instrucción completado",
 		log.IntAttr("pid", proceso.PID),
-		log.IntAttr("pc", proceso.PC))
-	return "Proceso ejecutado exitosamente"
+		log.IntAttr("pc", proceso.PC),
+		log.StringAttr("motivo", string(ctx.Motivo)))
+	return ctx
 }
@@ -7,7 +7,8 @@ import (
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
-// RecibirProcesos maneja la recepción de un proceso del kernel
+// RecibirProcesos maneja la recepción de un proceso del kernel. Responde apenas recibe el proceso: el contexto se
+// devuelve al kernel por /cpu/contexto cuando termina la ráfaga.
 func (h *Handler) RecibirProcesos(w http.ResponseWriter, r *http.Request) {
 	var proceso Proceso
 	if err := json.NewDecoder(r.Body).Decode(&proceso); err != nil {
@@ -21,22 +22,25 @@ func (h *Handler) RecibirProcesos(w http.ResponseWriter, r *http.Request) {
 		log.IntAttr("pid", proceso.PID),
 		log.IntAttr("pc", proceso.PC))
 
-	// Ejecutar el proceso en este CPU
-	msg := h.Ciclo(&proceso)
+	// Las direcciones lógicas del proceso se validan contra su tamaño
+	h.Service.MMU.EstablecerLimite(proceso.PID, proceso.Tamanio)
 
-	// Enviar respuesta con el nuevo PC
-	response := map[string]interface{}{
-		"pid":    proceso.PID,
-		"pc":     proceso.PC,
-		"motivo": msg,
-	}
+	// Ejecutar el proceso en este CPU sin bloquear al kernel
+	go h.ejecutarRafaga(proceso)
 
-	w.WriteHeader(http.StatusOK)
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.Log.Error("Error codificando respuesta",
-			log.ErrAttr(err))
-		http.Error(w, "Error interno del servidor", http.StatusInternalServerError)
-		return
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// ejecutarRafaga ejecuta el proceso hasta que deba devolver la CPU y le envía al kernel el contexto resultante
+func (h *Handler) ejecutarRafaga(proceso Proceso) {
+	ctx := h.Ciclo(&proceso)
+
+	if err := h.Service.DevolverContexto(ctx); err != nil {
+		h.Log.Error("Error devolviendo el contexto al kernel",
+			log.ErrAttr(err),
+			log.IntAttr("pid", ctx.PID),
+			log.IntAttr("pc", ctx.PC),
+			log.StringAttr("motivo", string(ctx.Motivo)))
 	}
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/sisoputnfrba/tp-golang/cpu/cmd/api"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
+	"github.com/sisoputnfrba/tp-golang/utils/metricas"
 )
 
 const (
@@ -38,6 +39,7 @@ func main() {
 	// Recepción de valores
 	mux.HandleFunc("POST /kernel/procesos", h.RecibirProcesos)             // Kernel --> CPU
 	mux.HandleFunc("POST /kernel/interrupciones", h.RecibirInterrupciones) // Kernel --> CPU
+	mux.Handle("GET /metrics", metricas.Handler())
 
 	// Nota: Le pasamos por argumento el puerto para que levante muchas CPUs
 	cpuAddress := fmt.Sprintf("%s:%d", h.Config.IpCpu, h.Config.PortCpu)
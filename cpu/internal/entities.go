@@ -1,5 +1,7 @@
 package internal
 
+import "github.com/sisoputnfrba/tp-golang/utils/contexto"
+
 const (
 	InterrupcionExcepcion TipoDeInterrupcion = "Excepcion"
 	InterrupcionExterna   TipoDeInterrupcion = "Externa"
@@ -18,6 +20,8 @@ type Interrupcion struct {
 	PID            int                `json:"pid"`
 	Tipo           TipoDeInterrupcion `json:"tipo"`
 	EsEnmascarable bool               `json:"es_enmascarable"`
+	Motivo         contexto.Motivo    `json:"motivo,omitempty"` // Motivo con el que se devuelve el proceso, en las de tipo Excepcion
+	Error          *contexto.Error    `json:"error,omitempty"`  // Detalle de las interrupciones de tipo Excepcion
 }
 
 type TipoDeInterrupcion string
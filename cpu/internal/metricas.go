@@ -0,0 +1,21 @@
+package internal
+
+import "github.com/sisoputnfrba/tp-golang/utils/metricas"
+
+const (
+	resultadoHit  = "hit"
+	resultadoMiss = "miss"
+)
+
+var (
+	MetricaInstrucciones = metricas.Default.Contador("cpu_instrucciones_total",
+		"Instrucciones ejecutadas, por tipo", "instruccion")
+	metricaAccesosTLB = metricas.Default.Contador("cpu_tlb_accesos_total",
+		"Accesos a la TLB, por algoritmo de reemplazo y resultado", "algoritmo", "resultado")
+	metricaReemplazosTLB = metricas.Default.Contador("cpu_tlb_reemplazos_total",
+		"Entradas de la TLB reemplazadas, por algoritmo", "algoritmo")
+	metricaAccesosCache = metricas.Default.Contador("cpu_cache_accesos_total",
+		"Accesos a la caché de páginas, por algoritmo de reemplazo y resultado", "algoritmo", "resultado")
+	metricaReemplazosCache = metricas.Default.Contador("cpu_cache_reemplazos_total",
+		"Páginas reemplazadas de la caché, por algoritmo", "algoritmo")
+)
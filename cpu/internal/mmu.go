@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/sisoputnfrba/tp-golang/cpu/pkg/memoria"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -24,6 +25,8 @@ type MMU struct {
 	CacheMutex     *sync.RWMutex
 	Memoria        *memoria.Memoria
 	Retardo        time.Duration // Retardo para operaciones de caché
+	LimitesMutex   *sync.RWMutex
+	limites        map[int]int // Tamaño de cada proceso, para detectar accesos fuera de rango
 }
 
 // TLB representa la Translation Lookaside Buffer
@@ -92,6 +95,8 @@ func NewMMU(tlbEntries, cacheEntries int, tlbAlgoritmo, cacheAlgoritmo string, l
 		NumberOfLevels: info.NumberOfLevels,
 		Memoria:        memoria,
 		Retardo:        retardoCache,
+		LimitesMutex:   &sync.RWMutex{},
+		limites:        make(map[int]int),
 	}
 }
 
@@ -104,6 +109,10 @@ func (m *MMU) TraducirDireccion(pid int, dirLogica string) (string, error) {
 		return "", err
 	}
 
+	if err = m.verificarAcceso(pid, dirLogicaInt, 1); err != nil {
+		return "", err
+	}
+
 	// Calcular número de página
 	// nro_página = floor(dirección_lógica / tamaño_página)
 	nroPagina := dirLogicaInt / m.PageSize
@@ -126,9 +135,10 @@ func (m *MMU) TraducirDireccion(pid int, dirLogica string) (string, error) {
 			// Log obligatorio: TLB Hit
 			// "PID: <PID> - TLB HIT - Pagina: <NUMERO_PAGINA>"
 			m.Log.Info(fmt.Sprintf("PID: %d - TLB HIT - Pagina: %d", pid, nroPagina))
+			metricaAccesosTLB.Inc(m.TLB.Algoritmo, resultadoHit)
 
 			// Actualizar estadísticas de TLB
-			tlbEntry.UltimoAcceso = time.Now()
+			tlbEntry.UltimoAcceso = clock.Now()
 			tlbEntry.ConteoDeAccesos++
 
 			// Calcular dirección física
@@ -143,6 +153,7 @@ func (m *MMU) TraducirDireccion(pid int, dirLogica string) (string, error) {
 			// Log obligatorio: TLB Miss
 			// "PID: <PID> - TLB MISS - Pagina: <NUMERO_PAGINA>"
 			m.Log.Info(fmt.Sprintf("PID: %d - TLB MISS - Pagina: %d", pid, nroPagina))
+			metricaAccesosTLB.Inc(m.TLB.Algoritmo, resultadoMiss)
 		}
 	}
 
@@ -181,10 +192,13 @@ func (m *MMU) TraducirDireccion(pid int, dirLogica string) (string, error) {
 // LeerConCache realiza una operación de lectura usando la caché si está habilitada.
 // Retorna el dato leído, la dirección física traducida y el número de página.
 func (m *MMU) LeerConCache(pid int, dirLogica string, tamanio int) error {
-	time.Sleep(m.Retardo) // Simular retardo de caché
+	clock.Sleep(m.Retardo) // Simular retardo de caché
 
 	// Calcular número de página
 	dirLogicaInt, _ := strconv.Atoi(dirLogica)
+	if err := m.verificarAcceso(pid, dirLogicaInt, tamanio); err != nil {
+		return err
+	}
 	nroPagina := dirLogicaInt / m.PageSize
 
 	entriesKey := m.calcularEntradasPorNivel(nroPagina)
@@ -230,10 +244,11 @@ func (m *MMU) LeerConCache(pid int, dirLogica string, tamanio int) error {
 			// Log obligatorio: Página encontrada en Caché
 			// "PID: <PID> - Cache Hit - Pagina: <NUMERO_PAGINA>"
 			m.Log.Info(fmt.Sprintf("PID: %d - Cache Hit - Pagina: %d", pid, nroPagina))
+			metricaAccesosCache.Inc(m.Cache.Algorithm, resultadoHit)
 
 			m.CacheMutex.Lock()
 			// Actualizar estadísticas de caché
-			entry.LastAccess = time.Now()
+			entry.LastAccess = clock.Now()
 			entry.Reference = true
 			m.CacheMutex.Unlock()
 
@@ -253,6 +268,7 @@ func (m *MMU) LeerConCache(pid int, dirLogica string, tamanio int) error {
 	// Log obligatorio: Página faltante en Caché
 	// "PID: <PID> - Cache Miss - Pagina: <NUMERO_PAGINA>"
 	m.Log.Info(fmt.Sprintf("PID: %d - Cache Miss - Pagina: %d", pid, nroPagina))
+	metricaAccesosCache.Inc(m.Cache.Algorithm, resultadoMiss)
 
 	// Traducir dirección lógica a física para obtener el número de página
 	dirFisica, err := m.TraducirDireccion(pid, dirLogica)
@@ -289,10 +305,13 @@ func (m *MMU) LeerConCache(pid int, dirLogica string, tamanio int) error {
 // EscribirConCache realiza una operación de escritura usando la caché si está habilitada.
 // Retorna la dirección física traducida y un error si ocurre.
 func (m *MMU) EscribirConCache(pid int, dirLogica, datos string) error {
-	time.Sleep(m.Retardo) // Simular retardo de caché
+	clock.Sleep(m.Retardo) // Simular retardo de caché
 
 	// Calcular número de página
 	dirLogicaInt, _ := strconv.Atoi(dirLogica)
+	if err := m.verificarAcceso(pid, dirLogicaInt, len(datos)); err != nil {
+		return err
+	}
 	nroPagina := dirLogicaInt / m.PageSize
 	nroPaginaStr := strconv.Itoa(nroPagina)
 
@@ -342,6 +361,7 @@ func (m *MMU) EscribirConCache(pid int, dirLogica, datos string) error {
 		// Log obligatorio: Página encontrada en Caché
 		// "PID: <PID> - Cache Hit - Pagina: <NUMERO_PAGINA>"
 		m.Log.Info(fmt.Sprintf("PID: %d - Cache Hit - Pagina: %s", pid, nroPaginaStr))
+		metricaAccesosCache.Inc(m.Cache.Algorithm, resultadoHit)
 
 		m.CacheMutex.Lock()
 		// Actualizar datos en caché
@@ -349,7 +369,7 @@ func (m *MMU) EscribirConCache(pid int, dirLogica, datos string) error {
 		dataAActualizar := []byte(m.Cache.Entries[index].Data)
 		copy(dataAActualizar[offset:offset+len(datos)], datos)
 		m.Cache.Entries[index].Data = string(dataAActualizar)
-		m.Cache.Entries[index].LastAccess = time.Now()
+		m.Cache.Entries[index].LastAccess = clock.Now()
 		m.Cache.Entries[index].Reference = true
 		m.Cache.Entries[index].Modified = true // Marcar como modificado
 		m.CacheMutex.Unlock()
@@ -360,6 +380,7 @@ func (m *MMU) EscribirConCache(pid int, dirLogica, datos string) error {
 	// Log obligatorio: Página faltante en Caché
 	// "PID: <PID> - Cache Miss - Pagina: <NUMERO_PAGINA>"
 	m.Log.Info(fmt.Sprintf("PID: %d - Cache Miss - Pagina: %s", pid, nroPaginaStr))
+	metricaAccesosCache.Inc(m.Cache.Algorithm, resultadoMiss)
 
 	// Traducir dirección lógica a física para obtener el número de página
 	dirFisica, err := m.TraducirDireccion(pid, dirLogica)
@@ -472,6 +493,9 @@ func (m *MMU) LimpiarMemoriaProceso(pid int) {
 	}
 	m.TLBMutex.Unlock()
 
+	// El kernel vuelve a enviar el tamaño del proceso cada vez que lo despacha
+	m.QuitarLimite(pid)
+
 	m.Log.Debug("Limpieza de memoria completada",
 		log.IntAttr("pid", pid))
 }
@@ -490,8 +514,8 @@ func (m *MMU) agregarATLB(entriesKey string, page, marco int) {
 	m.TLB.Entries[entriesKey] = &TLBEntry{
 		Page:            page,
 		Frame:           marco,
-		UltimoAcceso:    time.Now(),
-		TiempoCreacion:  time.Now(),
+		UltimoAcceso:    clock.Now(),
+		TiempoCreacion:  clock.Now(),
 		ConteoDeAccesos: 1,
 	}
 
@@ -514,7 +538,7 @@ func (m *MMU) evictTLBEntry() {
 func (m *MMU) evictTLBFIFO() {
 	var (
 		oldestKey  string
-		oldestTime = time.Now()
+		oldestTime = clock.Now()
 	)
 
 	for key, entry := range m.TLB.Entries {
@@ -526,6 +550,7 @@ func (m *MMU) evictTLBFIFO() {
 
 	if oldestKey != "" {
 		delete(m.TLB.Entries, oldestKey)
+		metricaReemplazosTLB.Inc(m.TLB.Algoritmo)
 		m.Log.Debug("Entrada TLB evictada (FIFO)",
 			log.StringAttr("key", oldestKey))
 	}
@@ -535,7 +560,7 @@ func (m *MMU) evictTLBFIFO() {
 func (m *MMU) evictTLBLRU() {
 	var (
 		lruKey  string
-		lruTime = time.Now()
+		lruTime = clock.Now()
 	)
 
 	for key, entry := range m.TLB.Entries {
@@ -547,6 +572,7 @@ func (m *MMU) evictTLBLRU() {
 
 	if lruKey != "" {
 		delete(m.TLB.Entries, lruKey)
+		metricaReemplazosTLB.Inc(m.TLB.Algoritmo)
 		m.Log.Debug("Entrada TLB evictada (LRU)",
 			log.StringAttr("key", lruKey))
 	}
@@ -565,7 +591,7 @@ func (m *MMU) agregarACache(pid int, entriesXPage, data string, modificado bool)
 		PID:        pid,
 		PageID:     entriesXPage,
 		Data:       data,
-		LastAccess: time.Now(),
+		LastAccess: clock.Now(),
 		Reference:  true,
 		Modified:   modificado,
 	})
@@ -642,6 +668,7 @@ func (m *MMU) evictCacheClock() {
 
 			// Actualizar el puntero del CLOCK
 			m.Cache.Clock = i
+			metricaReemplazosCache.Inc(m.Cache.Algorithm)
 
 			return
 		}
@@ -695,6 +722,7 @@ func (m *MMU) evictCacheClock() {
 
 			// Actualizar el puntero del CLOCK
 			m.Cache.Clock = i
+			metricaReemplazosCache.Inc(m.Cache.Algorithm)
 			return
 		}
 	}
@@ -721,6 +749,7 @@ func (m *MMU) evictCacheClockM() {
 
 			// Actualizar el puntero del CLOCK
 			m.Cache.Clock = i
+			metricaReemplazosCache.Inc(m.Cache.Algorithm)
 			return
 		}
 	}
@@ -773,6 +802,7 @@ func (m *MMU) evictCacheClockM() {
 
 			// Actualizar el puntero del CLOCK
 			m.Cache.Clock = i
+			metricaReemplazosCache.Inc(m.Cache.Algorithm)
 			return
 		}
 		entry.Reference = false // Limpiar reference bit
@@ -790,6 +820,7 @@ func (m *MMU) evictCacheClockM() {
 
 			// Actualizar el puntero del CLOCK
 			m.Cache.Clock = i
+			metricaReemplazosCache.Inc(m.Cache.Algorithm)
 			return
 		}
 	}
@@ -840,6 +871,7 @@ func (m *MMU) evictCacheClockM() {
 
 			// Actualizar el puntero del CLOCK
 			m.Cache.Clock = i
+			metricaReemplazosCache.Inc(m.Cache.Algorithm)
 			return
 		}
 	}
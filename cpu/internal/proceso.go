@@ -2,7 +2,9 @@ package internal
 
 import (
 	"encoding/json"
+	"time"
 
+	"github.com/sisoputnfrba/tp-golang/utils/contexto"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -14,6 +16,18 @@ func (s *Service) EnviarProcesoSyscall(syscall *ProcesoSyscall) error {
 	return s.Kernel.EnviarSyscall(body)
 }
 
+// DevolverContexto envía al kernel el contexto del proceso al terminar la ráfaga
+func (s *Service) DevolverContexto(ctx *contexto.Contexto) error {
+	body, _ := json.Marshal(ctx)
+	return s.Kernel.DevolverContexto(body)
+}
+
+// InformarAvance le informa al kernel cuánto lleva ejecutando la ráfaga del proceso
+func (s *Service) InformarAvance(pid int, rafaga time.Duration) error {
+	body, _ := json.Marshal(contexto.Avance{PID: pid, Rafaga: int(rafaga.Milliseconds())})
+	return s.Kernel.InformarAvance(body)
+}
+
 // LimpiarMemoriaProceso limpia la memoria (TLB y caché) cuando se desaloja un proceso
 func (s *Service) LimpiarMemoriaProceso(pid int) {
 	s.Log.Debug("Solicitando limpieza de memoria por desalojo de proceso",
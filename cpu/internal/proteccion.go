@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// ErrSegmentationFault se devuelve cuando un proceso accede a una dirección lógica fuera de su espacio de direcciones
+var ErrSegmentationFault = errors.New("SEGMENTATION_FAULT")
+
+// EstablecerLimite guarda el tamaño del proceso, que define el rango válido de sus direcciones lógicas. Un tamaño
+// menor o igual a 0 desactiva la verificación.
+func (m *MMU) EstablecerLimite(pid, tamanio int) {
+	if tamanio <= 0 {
+		m.Log.Warn("Proceso sin tamaño, no se verifican sus accesos a memoria",
+			log.IntAttr("pid", pid),
+			log.IntAttr("tamanio", tamanio),
+		)
+	}
+
+	m.LimitesMutex.Lock()
+	defer m.LimitesMutex.Unlock()
+
+	m.limites[pid] = tamanio
+}
+
+// QuitarLimite olvida el tamaño de un proceso que dejó la CPU, ya sea porque finalizó o porque se lo desalojó
+func (m *MMU) QuitarLimite(pid int) {
+	m.LimitesMutex.Lock()
+	defer m.LimitesMutex.Unlock()
+
+	delete(m.limites, pid)
+}
+
+// verificarAcceso controla que los bytes [dirLogica, dirLogica+tamanio) estén dentro del proceso
+func (m *MMU) verificarAcceso(pid, dirLogica, tamanio int) error {
+	m.LimitesMutex.RLock()
+	limite := m.limites[pid]
+	m.LimitesMutex.RUnlock()
+
+	if limite <= 0 {
+		return nil
+	}
+
+	if tamanio < 1 {
+		tamanio = 1
+	}
+	if dirLogica < 0 || dirLogica+tamanio > limite {
+		return fmt.Errorf("%w: acceso a [%d, %d) fuera del proceso %d de %d bytes",
+			ErrSegmentationFault, dirLogica, dirLogica+tamanio, pid, limite)
+	}
+
+	return nil
+}
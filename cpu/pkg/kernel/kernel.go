@@ -2,13 +2,23 @@ package kernel
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
+// ErrRecursoNoDisponible indica que el kernel no pudo atender la syscall sin bloquear al proceso (por ejemplo, un
+// WAIT sobre un recurso sin instancias libres): la CPU debe devolverle el contexto con la syscall
+var ErrRecursoNoDisponible = errors.New("el kernel no puede atender la syscall sin bloquear al proceso")
+
+// ErrSyscallRechazada indica que el kernel rechazó la syscall (argumentos inválidos, un recurso que no existe): el
+// proceso no puede continuar como si se hubiera atendido
+var ErrSyscallRechazada = errors.New("el kernel rechazó la syscall")
+
 type Kernel struct {
 	IP     string
 	Puerto int
@@ -35,11 +45,64 @@ func (k *Kernel) EnviarSyscall(body []byte) error {
 		return err
 	}
 
-	if resp != nil {
-		k.Log.Debug("Respuesta del servidor recibida.",
-			log.StringAttr("status", resp.Status),
-			log.AnyAttr("body", string(body)),
+	defer resp.Body.Close()
+
+	k.Log.Debug("Respuesta del servidor recibida.",
+		log.StringAttr("status", resp.Status),
+		log.AnyAttr("body", string(body)),
+	)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusConflict:
+		return ErrRecursoNoDisponible
+	}
+
+	respuesta, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		return fmt.Errorf("%w: %s", ErrSyscallRechazada, respuesta)
+	}
+	return fmt.Errorf("el kernel respondió con status %d: %s", resp.StatusCode, respuesta)
+}
+
+// DevolverContexto envía al kernel el contexto de ejecución de un proceso que terminó su ráfaga
+func (k *Kernel) DevolverContexto(body []byte) error {
+	url := fmt.Sprintf("http://%s:%d/cpu/contexto", k.IP, k.Puerto)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		k.Log.Error("Error devolviendo el contexto al Kernel",
+			log.StringAttr("ip", k.IP),
+			log.IntAttr("puerto", k.Puerto),
+			log.ErrAttr(err),
 		)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("el kernel rechazó el contexto con status %d", resp.StatusCode)
+	}
+
+	k.Log.Debug("Contexto devuelto al Kernel",
+		log.StringAttr("status", resp.Status),
+		log.AnyAttr("body", string(body)),
+	)
+
+	return nil
+}
+
+// InformarAvance le envía al kernel cuánto lleva ejecutando la ráfaga en curso, para que adelante su reloj virtual
+func (k *Kernel) InformarAvance(body []byte) error {
+	url := fmt.Sprintf("http://%s:%d/cpu/avance", k.IP, k.Puerto)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("el kernel rechazó el avance con status %d", resp.StatusCode)
 	}
 
 	return nil
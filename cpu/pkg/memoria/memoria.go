@@ -3,6 +3,7 @@ package memoria
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -12,6 +13,9 @@ import (
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
+// ErrPCFueraDeRango se devuelve cuando el PC no corresponde a ninguna instrucción del proceso
+var ErrPCFueraDeRango = errors.New("PC fuera del rango de instrucciones del proceso")
+
 // Memoria representa el cliente para comunicarse con el módulo de memoria
 type Memoria struct {
 	IP     string
@@ -337,6 +341,10 @@ func (m *Memoria) FetchInstruccion(pid int, pc int) (Instruccion, error) {
 		_ = Body.Close()
 	}(resp.Body)
 
+	if resp.StatusCode == http.StatusNotFound {
+		return instruccion, fmt.Errorf("%w: PID %d, PC %d", ErrPCFueraDeRango, pid, pc)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		m.Log.Error("Memoria respondió con error en fetch",
 			log.StringAttr("status", resp.Status),
@@ -6,6 +6,7 @@ type Config struct {
 	PortIo     int    `json:"port_io"`
 	IpIo       string `json:"ip_io"`
 	LogLevel   string `json:"log_level"`
+	ClockMode  string `json:"clock_mode"`
 }
 
 type IOIdentificacion struct {
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/config"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
@@ -31,6 +32,9 @@ func NewHandler(configFile, nombre string) *Handler {
 	// Initialize the logger with the log level from the configuration
 	logLevel := configStruct.LogLevel
 
+	// Initialize the clock (real or virtual) with the mode from the configuration
+	clock.Configurar(configStruct.ClockMode)
+
 	httpClient := &http.Client{
 		Timeout: 2 * time.Minute,
 	}
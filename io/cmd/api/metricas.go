@@ -0,0 +1,12 @@
+package api
+
+import "github.com/sisoputnfrba/tp-golang/utils/metricas"
+
+var (
+	metricaPeticionesAtendidas = metricas.Default.Contador("io_peticiones_atendidas_total",
+		"Peticiones de IO atendidas", "dispositivo")
+	metricaTiempoOcupado = metricas.Default.Contador("io_tiempo_ocupado_ms_total",
+		"Tiempo en milisegundos que el dispositivo estuvo atendiendo peticiones", "dispositivo")
+	metricaOcupado = metricas.Default.Gauge("io_ocupado",
+		"Si el dispositivo está atendiendo una petición (1) o no (0)", "dispositivo")
+)
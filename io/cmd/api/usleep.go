@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -33,7 +34,12 @@ func (h *Handler) EjecutarPeticion(w http.ResponseWriter, r *http.Request) {
 	)
 
 	// Simula el tiempo de espera
-	time.Sleep(time.Duration(usleep.TiempoSleep) * time.Millisecond)
+	metricaOcupado.Set(1, h.Nombre)
+	inicio := clock.Now()
+	clock.Sleep(time.Duration(usleep.TiempoSleep) * time.Millisecond)
+	metricaTiempoOcupado.Add(float64(clock.Since(inicio).Milliseconds()), h.Nombre)
+	metricaPeticionesAtendidas.Inc(h.Nombre)
+	metricaOcupado.Set(0, h.Nombre)
 
 	//Log obligatorio: Fin de IO
 	//"## PID: <PID> - Fin de IO".
@@ -10,6 +10,7 @@ import (
 
 	"github.com/sisoputnfrba/tp-golang/io/cmd/api"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
+	"github.com/sisoputnfrba/tp-golang/utils/metricas"
 )
 
 const (
@@ -87,6 +88,7 @@ func main() {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/kernel/usleep", h.EjecutarPeticion)
+	mux.Handle("GET /metrics", metricas.Handler())
 
 	err := http.ListenAndServe(fmt.Sprintf(":%d", h.Config.PortIo), mux)
 	if err != nil {
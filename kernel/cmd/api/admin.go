@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal/planificadores"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// FinalizarProcesoAdmin finaliza el proceso {pid} desde cualquier estado
+func (h *Handler) FinalizarProcesoAdmin(w http.ResponseWriter, r *http.Request) {
+	h.operacionAdmin(w, r, "finalizar", func(pid int) error {
+		if err := h.Planificador.ForzarFinalizacion(pid); err != nil {
+			return err
+		}
+
+		// Si esperaba un dispositivo IO, ya no tiene que usarlo
+		removerDeEsperaIO(pid)
+		return nil
+	})
+}
+
+// SuspenderProcesoAdmin suspende el proceso {pid} y lo swappea hasta que se lo reanude
+func (h *Handler) SuspenderProcesoAdmin(w http.ResponseWriter, r *http.Request) {
+	h.operacionAdmin(w, r, "suspender", h.Planificador.SuspenderManual)
+}
+
+// ReanudarProcesoAdmin reanuda el proceso {pid} suspendido desde la API, que pasa a SUSP.READY
+func (h *Handler) ReanudarProcesoAdmin(w http.ResponseWriter, r *http.Request) {
+	h.operacionAdmin(w, r, "reanudar", h.Planificador.ReanudarManual)
+}
+
+// operacionAdmin lee el PID de la ruta, ejecuta la operación y traduce su error a un código HTTP
+func (h *Handler) operacionAdmin(w http.ResponseWriter, r *http.Request, nombre string, operacion func(pid int) error) {
+	ctx := r.Context()
+
+	pid, err := strconv.Atoi(r.PathValue("pid"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("PID inválido"))
+		return
+	}
+
+	if err = operacion(pid); err != nil {
+		h.Log.WarnContext(ctx, "Error en operación administrativa",
+			log.ErrAttr(err),
+			log.StringAttr("operacion", nombre),
+			log.IntAttr("pid", pid),
+		)
+
+		switch {
+		case errors.Is(err, planificadores.ErrProcesoNoEncontrado):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, planificadores.ErrEstadoInvalido):
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// removerDeEsperaIO saca al proceso de las colas de espera de los dispositivos IO
+func removerDeEsperaIO(pid int) {
+	ioWaitQueuesMutex.Lock()
+	defer ioWaitQueuesMutex.Unlock()
+
+	for nombre, cola := range ioWaitQueues {
+		filtrada := cola[:0]
+		for _, espera := range cola {
+			if espera.PID != pid {
+				filtrada = append(filtrada, espera)
+			}
+		}
+		ioWaitQueues[nombre] = filtrada
+	}
+}
+
+// CambiarMultiprogramacion cambia el grado de multiprogramación en tiempo de ejecución. Recibe {"grado": n}, donde 0
+// quita el límite.
+func (h *Handler) CambiarMultiprogramacion(w http.ResponseWriter, r *http.Request) {
+	var pedido struct {
+		Grado *int `json:"grado"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&pedido); err != nil || pedido.Grado == nil || *pedido.Grado < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Se esperaba {\"grado\": n} con n mayor o igual a 0"))
+		return
+	}
+
+	h.Planificador.CambiarGradoMultiprogramacion(*pedido.Grado)
+
+	h.responderJSON(w, r, h.Planificador.Multiprogramacion())
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/internal/planificadores"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -188,6 +189,13 @@ func (h *Handler) ConexionInicialCPU(w http.ResponseWriter, r *http.Request) {
 		log.AnyAttr("CPUsConectadas", h.Planificador.CPUsConectadas),
 	)
 
+	// La CPU usa el mismo modo de reloj que el kernel, para que las ráfagas se midan en el mismo tiempo
+	modoReloj := clock.ModoReal
+	if clock.EsVirtual() {
+		modoReloj = clock.ModoVirtual
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte("ok"))
+	_ = json.NewEncoder(w).Encode(RespuestaConexionCPU{ClockMode: modoReloj})
 }
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/kernel/internal/consola"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+const promptConsola = "kernel> "
+
+// comandoConsola es un comando de la consola del kernel
+type comandoConsola struct {
+	uso      string
+	minArgs  int
+	ejecutar func(h *Handler, args []string) error
+}
+
+var comandosConsola = map[string]comandoConsola{
+	"INICIAR_PROCESO": {
+		uso:      "INICIAR_PROCESO <archivo> <tamaño> [prioridad] [tickets]",
+		minArgs:  2,
+		ejecutar: (*Handler).consolaIniciarProceso,
+	},
+	"FINALIZAR_PROCESO": {
+		uso:      "FINALIZAR_PROCESO <pid>",
+		minArgs:  1,
+		ejecutar: (*Handler).consolaFinalizarProceso,
+	},
+	"DETENER_PLANIFICACION": {
+		uso: "DETENER_PLANIFICACION",
+		ejecutar: func(h *Handler, _ []string) error {
+			h.Planificador.DetenerPlanificacion()
+			return nil
+		},
+	},
+	"INICIAR_PLANIFICACION": {
+		uso: "INICIAR_PLANIFICACION",
+		ejecutar: func(h *Handler, _ []string) error {
+			h.Planificador.IniciarPlanificacion()
+			return nil
+		},
+	},
+	"PROCESO_ESTADO": {
+		uso:      "PROCESO_ESTADO",
+		ejecutar: (*Handler).consolaProcesoEstado,
+	},
+	"MULTIPROGRAMACION": {
+		uso:      "MULTIPROGRAMACION <grado>",
+		minArgs:  1,
+		ejecutar: (*Handler).consolaMultiprogramacion,
+	},
+}
+
+// NuevaConsola crea la consola del kernel con autocompletado de sus comandos
+func NuevaConsola() *consola.Consola {
+	nombres := make([]string, 0, len(comandosConsola))
+	for nombre := range comandosConsola {
+		nombres = append(nombres, nombre)
+	}
+
+	return consola.New(promptConsola, nombres)
+}
+
+// EjecutarConsola lee y ejecuta comandos hasta que se cierre la entrada. La planificación arranca detenida: se inicia
+// con INICIAR_PLANIFICACION o, como antes, presionando Enter.
+func (h *Handler) EjecutarConsola(c *consola.Consola) {
+	fmt.Println("Presione Enter o ingrese INICIAR_PLANIFICACION para iniciar la planificación")
+
+	for {
+		linea, err := c.LeerLinea()
+		if err != nil {
+			h.Log.Debug("Consola cerrada", log.ErrAttr(err))
+			return
+		}
+
+		campos := strings.Fields(linea)
+		if len(campos) == 0 {
+			if !h.Planificador.PlanificacionActiva() {
+				h.Planificador.IniciarPlanificacion()
+			}
+			continue
+		}
+
+		nombre := strings.ToUpper(campos[0])
+		comando, ok := comandosConsola[nombre]
+		if !ok {
+			fmt.Printf("Comando desconocido: %s\n", campos[0])
+			continue
+		}
+
+		args := campos[1:]
+		if len(args) < comando.minArgs {
+			fmt.Printf("Uso: %s\n", comando.uso)
+			continue
+		}
+
+		if err = comando.ejecutar(h, args); err != nil {
+			fmt.Printf("Error en %s: %v\n", nombre, err)
+		}
+	}
+}
+
+func (h *Handler) consolaIniciarProceso(args []string) error {
+	var prioridad, tickets int
+	var err error
+
+	if len(args) > 2 {
+		if prioridad, err = strconv.Atoi(args[2]); err != nil || prioridad < 0 {
+			return errors.New("la prioridad debe ser un número entero no negativo")
+		}
+	}
+	if len(args) > 3 {
+		if tickets, err = strconv.Atoi(args[3]); err != nil || tickets <= 0 {
+			return errors.New("los tickets deben ser un número entero positivo")
+		}
+	}
+
+	proceso := h.crearProceso(args[0], args[1], prioridad, tickets)
+
+	h.Planificador.CanalNuevoProcesoNew <- proceso
+
+	//Log obligatorio: Creación de proceso
+	//"## (<PID>) Se crea el proceso - Estado: NEW"
+	h.Log.Info(fmt.Sprintf("## (%d) Se crea el proceso - Estado: NEW", proceso.PCB.PID))
+
+	return nil
+}
+
+func (h *Handler) consolaFinalizarProceso(args []string) error {
+	pid, err := strconv.Atoi(args[0])
+	if err != nil {
+		return errors.New("PID inválido")
+	}
+
+	if err = h.Planificador.ForzarFinalizacion(pid); err != nil {
+		return err
+	}
+	removerDeEsperaIO(pid)
+
+	return nil
+}
+
+// consolaProcesoEstado lista los procesos de cada estado
+func (h *Handler) consolaProcesoEstado(_ []string) error {
+	colas := h.Planificador.Colas()
+
+	estado := "DETENIDA"
+	if h.Planificador.PlanificacionActiva() {
+		estado = "INICIADA"
+	}
+	fmt.Printf("Planificación %s\n", estado)
+
+	multiprogramacion := h.Planificador.Multiprogramacion()
+	fmt.Printf("Multiprogramación: %d en memoria, grado %d, %d esperando por el grado\n",
+		multiprogramacion.EnMemoria, multiprogramacion.Grado, multiprogramacion.Esperando)
+
+	for _, e := range []internal.Estado{
+		internal.EstadoNew,
+		internal.EstadoReady,
+		internal.EstadoExec,
+		internal.EstadoBloqueado,
+		internal.EstadoSuspReady,
+		internal.EstadoSuspBloqueado,
+	} {
+		pids := make([]string, 0, len(colas[e]))
+		for _, pid := range colas[e] {
+			pids = append(pids, strconv.Itoa(pid))
+		}
+		fmt.Printf("%-13s (%d): %s\n", e, len(pids), strings.Join(pids, ", "))
+	}
+
+	return nil
+}
+
+func (h *Handler) consolaMultiprogramacion(args []string) error {
+	grado, err := strconv.Atoi(args[0])
+	if err != nil || grado < 0 {
+		return errors.New("el grado de multiprogramación debe ser un número entero no negativo (0 sin límite)")
+	}
+
+	h.Planificador.CambiarGradoMultiprogramacion(grado)
+	return nil
+}
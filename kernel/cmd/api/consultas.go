@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// estadoIOs es la respuesta de GET /ios: los dispositivos conectados y los procesos que esperan cada uno
+type estadoIOs struct {
+	Dispositivos []IOIdentificacion      `json:"dispositivos"`
+	ColasEspera  map[string][]IOWaitInfo `json:"colas_espera"`
+}
+
+// ConsultarProcesos devuelve todos los procesos que todavía no finalizaron
+func (h *Handler) ConsultarProcesos(w http.ResponseWriter, r *http.Request) {
+	h.responderJSON(w, r, h.Planificador.Procesos())
+}
+
+// ConsultarProceso devuelve el PCB, el estado, las métricas y la estimación SJF del proceso {pid}
+func (h *Handler) ConsultarProceso(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.PathValue("pid"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("PID inválido"))
+		return
+	}
+
+	proceso, ok := h.Planificador.Proceso(pid)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("Proceso no encontrado"))
+		return
+	}
+
+	h.responderJSON(w, r, proceso)
+}
+
+// ConsultarColas devuelve los PIDs de cada cola del planificador
+func (h *Handler) ConsultarColas(w http.ResponseWriter, r *http.Request) {
+	h.responderJSON(w, r, h.Planificador.Colas())
+}
+
+// ConsultarCPUs devuelve las CPUs conectadas con el PID que ejecuta cada una
+func (h *Handler) ConsultarCPUs(w http.ResponseWriter, r *http.Request) {
+	h.responderJSON(w, r, h.Planificador.CPUs())
+}
+
+// ConsultarIOs devuelve los dispositivos IO conectados y sus colas de espera
+func (h *Handler) ConsultarIOs(w http.ResponseWriter, r *http.Request) {
+	var respuesta estadoIOs
+
+	ioIdentificacionMutex.RLock()
+	respuesta.Dispositivos = append(make([]IOIdentificacion, 0, len(ioIdentificacion)), ioIdentificacion...)
+	ioIdentificacionMutex.RUnlock()
+
+	ioWaitQueuesMutex.RLock()
+	respuesta.ColasEspera = make(map[string][]IOWaitInfo, len(ioWaitQueues))
+	for nombre, cola := range ioWaitQueues {
+		respuesta.ColasEspera[nombre] = append(make([]IOWaitInfo, 0, len(cola)), cola...)
+	}
+	ioWaitQueuesMutex.RUnlock()
+
+	h.responderJSON(w, r, respuesta)
+}
+
+// responderJSON serializa la respuesta de una consulta
+func (h *Handler) responderJSON(w http.ResponseWriter, r *http.Request, respuesta any) {
+	body, err := json.Marshal(respuesta)
+	if err != nil {
+		h.Log.ErrorContext(r.Context(), "Error al serializar la respuesta",
+			log.ErrAttr(err),
+			log.StringAttr("ruta", r.URL.Path),
+		)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Error al serializar la respuesta"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// ConsultarMultiprogramacion devuelve el grado de multiprogramación, los procesos en memoria y los que esperan por él
+func (h *Handler) ConsultarMultiprogramacion(w http.ResponseWriter, r *http.Request) {
+	h.responderJSON(w, r, h.Planificador.Multiprogramacion())
+}
@@ -3,20 +3,38 @@ package api
 import "sync"
 
 type Config struct {
-	IpMemory              string  `json:"ip_memory"`
-	PortMemory            int     `json:"port_memory"`
-	IpKernel              string  `json:"ip_kernel"`
-	PortKernel            int     `json:"port_kernel"`
-	IpIo                  string  `json:"ip_io"`
-	PortIo                int     `json:"port_io"`
-	IpCPU                 string  `json:"ip_cpu"`
-	PortCPU               int     `json:"port_cpu"`
-	SchedulerAlgorithm    string  `json:"scheduler_algorithm"`
-	ReadyIngressAlgorithm string  `json:"ready_ingress_algorithm"`
-	Alpha                 float64 `json:"alpha"`
-	InitialEstimate       int     `json:"initial_estimate"`
-	SuspensionTime        int     `json:"suspension_time"`
-	LogLevel              string  `json:"log_level"`
+	IpMemory               string         `json:"ip_memory"`
+	PortMemory             int            `json:"port_memory"`
+	IpKernel               string         `json:"ip_kernel"`
+	PortKernel             int            `json:"port_kernel"`
+	IpIo                   string         `json:"ip_io"`
+	PortIo                 int            `json:"port_io"`
+	IpCPU                  string         `json:"ip_cpu"`
+	PortCPU                int            `json:"port_cpu"`
+	SchedulerAlgorithm     string         `json:"scheduler_algorithm"`
+	ReadyIngressAlgorithm  string         `json:"ready_ingress_algorithm"`
+	Alpha                  float64        `json:"alpha"`
+	InitialEstimate        int            `json:"initial_estimate"`
+	SuspensionTime         int            `json:"suspension_time"`
+	SuspensionPolicy       string         `json:"suspension_policy"`
+	ChildrenPolicy         string         `json:"children_policy"`    // ORPHAN (por defecto) o CASCADE
+	Resources              map[string]int `json:"resources"`          // Recursos para WAIT/SIGNAL, con sus instancias iniciales
+	DeadlockDetection      string         `json:"deadlock_detection"` // ON_BLOCK (por defecto), PERIODIC o NONE
+	DeadlockInterval       int            `json:"deadlock_interval"`  // Milisegundos entre búsquedas con PERIODIC
+	DeadlockRecovery       string         `json:"deadlock_recovery"`  // NONE (por defecto), KILL_YOUNGEST o KILL_LOWEST_PRIORITY
+	MailboxCapacity        int            `json:"mailbox_capacity"`   // Mensajes que entran en cada mailbox de SEND/RECV
+	Quantum                int            `json:"quantum"`
+	MLFQQuantums           []int          `json:"mlfq_quantums"`
+	MLFQBoost              int            `json:"mlfq_boost"`
+	AgingThreshold         int            `json:"aging_threshold"`
+	PerCpuQueues           bool           `json:"per_cpu_queues"`
+	GradoMultiprogramacion int            `json:"grado_multiprogramacion"`
+	DefaultTickets         int            `json:"default_tickets"`
+	RngSeed                int64          `json:"rng_seed"`
+	LogLevel               string         `json:"log_level"`
+	ClockMode              string         `json:"clock_mode"`
+	TimelinePath           string         `json:"timeline_path"`
+	ReportePath            string         `json:"reporte_path"` // Directorio del reporte de procesos finalizados, vacío lo deshabilita
 }
 
 // Se usa para almacenar las IOs
@@ -44,6 +62,11 @@ type IOIdentificacion struct {
 	Cola      string `json:"cola"` // Cola a la que pertenece la el proceso (por ejemplo, "ready", "blocked", etc.)
 }
 
+// RespuestaConexionCPU es lo que el kernel le responde a una CPU que se conecta
+type RespuestaConexionCPU struct {
+	ClockMode string `json:"clock_mode"` // Modo del reloj del kernel, que la CPU también usa
+}
+
 // Inicializar las colas de espera para IO
 // Nota: La función init() se ejecuta automáticamente cuando se importa el paquete,
 // antes de que se ejecute cualquier otra función
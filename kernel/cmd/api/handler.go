@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/internal/planificadores"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/config"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 	uniqueid "github.com/sisoputnfrba/tp-golang/utils/unique-id"
@@ -33,27 +34,70 @@ func NewHandler(configFile string) *Handler {
 
 	// Initialize the logger with the log level from the configuration
 	logLevel := configStruct.LogLevel
+
+	// Initialize the clock (real or virtual) with the mode from the configuration
+	clock.Configurar(configStruct.ClockMode)
 	logger := log.BuildLogger(logLevel)
 
 	httpClient := &http.Client{
 		Timeout: 2 * time.Minute,
 	}
 
+	planificador := planificadores.NewPlanificador(
+		logger, configStruct.IpMemory,
+		configStruct.ReadyIngressAlgorithm, configStruct.SchedulerAlgorithm,
+		configStruct.PortMemory,
+		&planificadores.SjfConfig{
+			Alpha:           configStruct.Alpha,
+			InitialEstimate: configStruct.InitialEstimate,
+		},
+		&planificadores.MedianoPlazoConfig{
+			SuspensionTime: configStruct.SuspensionTime,
+			Politica:       configStruct.SuspensionPolicy,
+		},
+		&planificadores.RoundRobinConfig{
+			Quantum: configStruct.Quantum,
+		},
+		&planificadores.MLFQConfig{
+			Quantums: configStruct.MLFQQuantums,
+			Boost:    configStruct.MLFQBoost,
+		},
+		&planificadores.PrioridadesConfig{
+			AgingThreshold: configStruct.AgingThreshold,
+		},
+		&planificadores.ProporcionalConfig{
+			TicketsPorDefecto: configStruct.DefaultTickets,
+			Semilla:           configStruct.RngSeed,
+		},
+		configStruct.PerCpuQueues,
+		configStruct.GradoMultiprogramacion,
+		httpClient,
+	)
+
+	if configStruct.ReportePath != "" {
+		reporte, err := planificadores.NewReporte(configStruct.ReportePath)
+		if err != nil {
+			logger.Error("Error al crear el reporte de finalización, no se reportarán los procesos",
+				log.ErrAttr(err),
+				log.StringAttr("directorio", configStruct.ReportePath),
+			)
+		}
+		planificador.Reporte = reporte
+	}
+	planificador.UsarPoliticaHijos(configStruct.ChildrenPolicy)
+	planificador.ConfigurarRecursos(configStruct.Resources)
+	planificador.ConfigurarDeadlocks(&planificadores.DeadlockConfig{
+		Deteccion:    configStruct.DeadlockDetection,
+		Intervalo:    configStruct.DeadlockInterval,
+		Recuperacion: configStruct.DeadlockRecovery,
+	})
+	planificador.ConfigurarMailboxes(configStruct.MailboxCapacity)
+
 	return &Handler{
-		Config: configStruct,
-		Log:    logger,
-		Planificador: planificadores.NewPlanificador(
-			logger, configStruct.IpMemory,
-			configStruct.ReadyIngressAlgorithm, configStruct.SchedulerAlgorithm,
-			configStruct.PortMemory,
-			&planificadores.SjfConfig{
-				Alpha:           configStruct.Alpha,
-				InitialEstimate: configStruct.InitialEstimate,
-			},
-			configStruct.SuspensionTime,
-			httpClient,
-		),
-		UniqueID:   uniqueid.Init(),
-		HttpClient: httpClient,
+		Config:       configStruct,
+		Log:          logger,
+		Planificador: planificador,
+		UniqueID:     uniqueid.Init(),
+		HttpClient:   httpClient,
 	}
 }
@@ -22,6 +22,9 @@ func (h *Handler) TerminoPeticionIO(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Con el reloj virtual, el kernel adelanta su reloj hasta el fin de la IO antes de procesarlo
+	h.Planificador.AvanzarRelojFinIO(ioIdentificacionPeticion.ProcesoID)
+
 	// Buscar el dispositivo IO y marcarlo como libre
 	ioIdentificacionMutex.Lock()
 	encontrado := false
@@ -2,39 +2,66 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/kernel/internal/planificadores"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/contexto"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
+var (
+	// errSyscallInvalida indica que la syscall no se reconoce o le faltan argumentos
+	errSyscallInvalida = errors.New("syscall inválida")
+	// errDebeBloquearse indica que la syscall no se puede atender mientras el proceso sigue ejecutando: la CPU debe
+	// devolver el contexto con la syscall
+	errDebeBloquearse = errors.New("la syscall requiere bloquear al proceso")
+)
+
 type rtaCPU struct {
 	PID         int      `json:"pid"`
 	PC          int      `json:"pc"`
 	Instruccion string   `json:"instruccion"`
 	Args        []string `json:"args,omitempty"`
+
+	conContexto bool // Llegó con el contexto: el proceso ya devolvió la CPU
+	reintento   bool // Ya había llegado por /cpu/proceso y se la devolvió con errDebeBloquearse
 }
 
 // crearProceso crea un nuevo proceso con las métricas inicializadas correctamente
-func (h *Handler) crearProceso(nombreArchivo, tamanioProceso string) *internal.Proceso {
+func (h *Handler) crearProceso(nombreArchivo, tamanioProceso string, prioridad, tickets int) *internal.Proceso {
+	if tickets <= 0 {
+		tickets = h.ticketsPorDefecto()
+	}
+
+	tamanioBytes, _ := strconv.Atoi(tamanioProceso)
+	pid := h.UniqueID.GetUniqueID()
+
 	proceso := &internal.Proceso{
 		PCB: &internal.PCB{
-			PID:                h.UniqueID.GetUniqueID(),
+			PID:                pid,
 			PC:                 0,
 			MetricasTiempo:     map[internal.Estado]*internal.EstadoTiempo{},
 			MetricasEstado:     map[internal.Estado]int{},
 			Tamanio:            tamanioProceso,
+			TamanioBytes:       tamanioBytes,
 			NombreArchivo:      nombreArchivo,
 			EstimacionAnterior: float64(h.Config.InitialEstimate * 1000), // Convertir a milisegundos
+			Prioridad:          prioridad,
+			PrioridadEfectiva:  prioridad,
+			Tickets:            tickets,
+			PIDRaiz:            pid, // Si lo crea otro proceso, hereda la raíz de su padre
 		},
 	}
 
 	// Inicializar métricas de tiempo para estado NEW
 	proceso.PCB.MetricasTiempo[internal.EstadoNew] = &internal.EstadoTiempo{
-		TiempoInicio:    time.Now(),
+		TiempoInicio:    clock.Now(),
 		TiempoAcumulado: 0,
 	}
 
@@ -55,13 +82,24 @@ func (h *Handler) crearProceso(nombreArchivo, tamanioProceso string) *internal.P
 		}
 	}
 
+	h.Planificador.RegistrarCreacion(proceso.PCB.PID)
+
 	return proceso
 }
 
+// ticketsPorDefecto devuelve los tickets de los procesos que no los indican al crearse
+func (h *Handler) ticketsPorDefecto() int {
+	if h.Config.DefaultTickets > 0 {
+		return h.Config.DefaultTickets
+	}
+
+	return planificadores.TicketsPorDefecto
+}
+
 // EjecutarPlanificadores envia un proceso a la Memoria
-func (h *Handler) EjecutarPlanificadores(archivoNombre, tamanioProceso string) {
+func (h *Handler) EjecutarPlanificadores(archivoNombre, tamanioProceso string, prioridad, tickets int) {
 	// Creo un proceso con métricas inicializadas correctamente
-	proceso := h.crearProceso(archivoNombre, tamanioProceso)
+	proceso := h.crearProceso(archivoNombre, tamanioProceso, prioridad, tickets)
 
 	go h.Planificador.PlanificadorLargoPlazo()
 	go h.Planificador.PlanificadorCortoPlazo()
@@ -90,21 +128,150 @@ func (h *Handler) RespuestaProcesoCPU(w http.ResponseWriter, r *http.Request) {
 		log.AnyAttr("syscall", syscall),
 	)
 
+	if err = h.atenderSyscall(syscall); err != nil {
+		if errors.Is(err, errDebeBloquearse) {
+			w.WriteHeader(http.StatusConflict)
+		} else if errors.Is(err, errSyscallInvalida) {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// AvanceRafaga recibe lo que lleva ejecutando la ráfaga en curso de un proceso, que la CPU informa con el reloj
+// virtual después de cada instrucción
+func (h *Handler) AvanceRafaga(w http.ResponseWriter, r *http.Request) {
+	var avance contexto.Avance
+	if err := json.NewDecoder(r.Body).Decode(&avance); err != nil {
+		h.Log.Error("Error al decodificar el avance de la ráfaga",
+			log.ErrAttr(err),
+		)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Error al decodificar el avance de la ráfaga"))
+		return
+	}
+
+	if err := h.Planificador.AvanzarRelojRafaga(avance); err != nil {
+		h.Log.Warn("Avance de una ráfaga desconocida",
+			log.ErrAttr(err),
+			log.IntAttr("pid", avance.PID),
+		)
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// RecibirContexto recibe el contexto que devuelve la CPU al terminar una ráfaga. Desalojos, syscalls bloqueantes y
+// EXIT llegan todos por acá.
+func (h *Handler) RecibirContexto(w http.ResponseWriter, r *http.Request) {
+	var ctx contexto.Contexto
+	if err := json.NewDecoder(r.Body).Decode(&ctx); err != nil {
+		h.Log.Error("Error al decodificar el contexto de la CPU",
+			log.ErrAttr(err),
+		)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Error al decodificar el contexto de la CPU"))
+		return
+	}
+
+	h.Log.Debug("Me llego el contexto del Proceso",
+		log.AnyAttr("contexto", ctx),
+	)
+
+	var atenderSyscall func()
+	if ctx.Syscall != nil {
+		syscall := rtaCPU{
+			PID:         ctx.PID,
+			PC:          ctx.PC,
+			Instruccion: ctx.Syscall.Instruccion,
+			Args:        ctx.Syscall.Args,
+			conContexto: true,
+			reintento:   ctx.Syscall.Reintento,
+		}
+		atenderSyscall = func() {
+			if err := h.atenderSyscall(syscall); err != nil {
+				h.Log.Error("Error al atender la syscall devuelta con el contexto",
+					log.ErrAttr(err),
+					log.IntAttr("pid", syscall.PID),
+					log.StringAttr("syscall", syscall.Instruccion),
+				)
+			}
+		}
+	}
+
+	if err := h.Planificador.RecibirContexto(ctx, atenderSyscall); err != nil {
+		h.Log.Warn("Contexto de una ráfaga desconocida",
+			log.ErrAttr(err),
+			log.IntAttr("pid", ctx.PID),
+		)
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// atenderSyscall ejecuta la syscall de un proceso. Las syscalls bloqueantes llegan junto con el contexto que devuelve
+// la CPU, e INIT_PROC, WAIT <recurso>, SIGNAL y SEND por /cpu/proceso mientras el proceso sigue ejecutando. Si un
+// WAIT, SIGNAL o SEND no se puede atender así, se devuelve errDebeBloquearse y vuelve a llegar con el contexto.
+func (h *Handler) atenderSyscall(syscall rtaCPU) error {
+	var err error
+
 	//Log obligatorio: Syscall recibida
 	//"## (<PID>) - Solicitó syscall: <NOMBRE_SYSCALL>"
-	h.Log.Info(fmt.Sprintf("## (%d) - Solicitó syscall: %s", syscall.PID, syscall.Instruccion))
+	if !syscall.reintento {
+		h.Log.Info(fmt.Sprintf("## (%d) - Solicitó syscall: %s", syscall.PID, syscall.Instruccion))
+		planificadores.ContarSyscall(syscall.Instruccion)
+	}
 
 	switch syscall.Instruccion {
 	case "INIT_PROC":
 		// Verifico que tenga los argumentos necesarios
 		if len(syscall.Args) < 2 {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = w.Write([]byte("Error: no se recibieron los argumentos necesarios (archivo y tamaño)"))
-			return
+			return fmt.Errorf("%w: no se recibieron los argumentos necesarios (archivo y tamaño)", errSyscallInvalida)
+		}
+
+		// La prioridad es opcional, por defecto es 0 (la mayor)
+		var prioridad int
+		if len(syscall.Args) > 2 {
+			prioridad, err = strconv.Atoi(syscall.Args[2])
+			if err != nil || prioridad < 0 {
+				h.Log.Warn("Prioridad inválida en INIT_PROC, se usa la prioridad por defecto",
+					log.IntAttr("pid", syscall.PID),
+					log.StringAttr("prioridad", syscall.Args[2]),
+				)
+				prioridad = 0
+			}
+		}
+
+		// Los tickets (LOTTERY, STRIDE) también son opcionales, por defecto se usan los de la configuración
+		var tickets int
+		if len(syscall.Args) > 3 {
+			tickets, err = strconv.Atoi(syscall.Args[3])
+			if err != nil || tickets <= 0 {
+				h.Log.Warn("Tickets inválidos en INIT_PROC, se usan los tickets por defecto",
+					log.IntAttr("pid", syscall.PID),
+					log.StringAttr("tickets", syscall.Args[3]),
+				)
+				tickets = 0
+			}
 		}
 
 		// Creo un proceso hijo con métricas inicializadas correctamente
-		proceso := h.crearProceso(syscall.Args[0], syscall.Args[1])
+		proceso := h.crearProceso(syscall.Args[0], syscall.Args[1], prioridad, tickets)
+		h.Planificador.RegistrarHijo(syscall.PID, proceso)
 
 		h.Planificador.CanalNuevoProcesoNew <- proceso
 
@@ -128,7 +295,7 @@ func (h *Handler) RespuestaProcesoCPU(w http.ResponseWriter, r *http.Request) {
 		if !existeIO {
 			//No existe la IO, se manda a EXIT
 			go h.Planificador.FinalizarProcesoEnCualquierCola(syscall.PID)
-			return
+			return nil
 
 		} else {
 			// Buscar proceso en EXEC
@@ -137,7 +304,7 @@ func (h *Handler) RespuestaProcesoCPU(w http.ResponseWriter, r *http.Request) {
 					log.IntAttr("pid", syscall.PID),
 				)
 
-				return
+				return nil
 			}
 
 			//Existe y está libre, pasar a blocked y además manda la señal
@@ -146,21 +313,18 @@ func (h *Handler) RespuestaProcesoCPU(w http.ResponseWriter, r *http.Request) {
 				h.Log.Error("Error convirtiendo a int",
 					log.ErrAttr(err),
 				)
-				return
+				return nil
 			}
 
 			// Bloquear el proceso
-			err = h.Planificador.BloquearPorIO(syscall.PID)
+			err = h.Planificador.BloquearPorIO(syscall.PID, ioBuscada)
 			if err != nil {
 				h.Log.Debug("Error al bloquear proceso por IO",
 					log.ErrAttr(err),
 					log.IntAttr("pid", syscall.PID),
 				)
 
-				w.WriteHeader(http.StatusInternalServerError)
-				_, _ = w.Write([]byte("{\"error\":\"error al bloquear proceso por IO\"}"))
-
-				return
+				return fmt.Errorf("error al bloquear proceso por IO: %w", err)
 			}
 
 			//Log obligatorio: Motivo de Bloqueo
@@ -211,23 +375,164 @@ func (h *Handler) RespuestaProcesoCPU(w http.ResponseWriter, r *http.Request) {
 				)
 			}
 
-			return
+			return nil
 		}
 
 	case "DUMP_MEMORY":
 		/* Se bloquea el proceso. En caso de error, se envía a la cola de Exit. Caso contrario, se pasa a Ready*/
 		go h.Planificador.RealizarDumpMemory(syscall.PID)
 
+	case "WAIT":
+		if len(syscall.Args) < 1 {
+			if syscall.conContexto {
+				go h.Planificador.FinalizarProcesoEnCualquierCola(syscall.PID)
+			}
+			return fmt.Errorf("%w: no se recibió el recurso o el PID a esperar", errSyscallInvalida)
+		}
+
+		// WAIT <recurso> toma una instancia de un semáforo del kernel
+		if h.Planificador.ExisteRecurso(syscall.Args[0]) {
+			return h.esperarRecurso(syscall)
+		}
+		if !syscall.conContexto {
+			// Ningún recurso se llama así: se resuelve cuando la CPU devuelva el contexto
+			return errDebeBloquearse
+		}
+
+		// WAIT <pid|ANY> espera a un hijo en particular o a cualquiera de ellos
+		objetivo := planificadores.EsperarCualquiera
+		if !strings.EqualFold(syscall.Args[0], "ANY") {
+			if objetivo, err = strconv.Atoi(syscall.Args[0]); err != nil || objetivo <= 0 {
+				h.recursoInexistente(syscall)
+				return nil
+			}
+		}
+
+		if err = h.Planificador.Esperar(syscall.PID, objetivo); err != nil {
+			return fmt.Errorf("error al bloquear proceso por WAIT: %w", err)
+		}
+
+	case "SIGNAL":
+		if len(syscall.Args) < 1 {
+			if syscall.conContexto {
+				go h.Planificador.FinalizarProcesoEnCualquierCola(syscall.PID)
+			}
+			return fmt.Errorf("%w: no se recibió el recurso a liberar", errSyscallInvalida)
+		}
+
+		// SIGNAL nunca bloquea: la CPU solo devuelve el contexto si el recurso no existe
+		if !h.Planificador.ExisteRecurso(syscall.Args[0]) {
+			if !syscall.conContexto {
+				return errDebeBloquearse
+			}
+			h.recursoInexistente(syscall)
+			return nil
+		}
+
+		if err = h.Planificador.LiberarRecurso(syscall.PID, syscall.Args[0]); err != nil {
+			return fmt.Errorf("error al liberar recurso: %w", err)
+		}
+
+	case "SEND":
+		if len(syscall.Args) < 2 {
+			if syscall.conContexto {
+				go h.Planificador.FinalizarProcesoEnCualquierCola(syscall.PID)
+			}
+			return fmt.Errorf("%w: no se recibieron el destino y el mensaje", errSyscallInvalida)
+		}
+
+		// Mientras el proceso ejecuta solo se deposita si hay lugar; si no, vuelve con el contexto y se lo bloquea
+		if !syscall.conContexto {
+			depositado, err := h.Planificador.DepositarMensaje(syscall.PID, syscall.Args[0], syscall.Args[1])
+			if err != nil {
+				return fmt.Errorf("%w: %w", errSyscallInvalida, err)
+			}
+			if !depositado {
+				return errDebeBloquearse
+			}
+			return nil
+		}
+
+		err = h.Planificador.EnviarMensaje(syscall.PID, syscall.Args[0], syscall.Args[1])
+		if errors.Is(err, planificadores.ErrMailboxInexistente) {
+			h.recursoInexistente(syscall)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error al bloquear proceso por SEND: %w", err)
+		}
+
+	case "RECV":
+		if len(syscall.Args) < 2 {
+			if syscall.conContexto {
+				go h.Planificador.FinalizarProcesoEnCualquierCola(syscall.PID)
+			}
+			return fmt.Errorf("%w: no se recibieron el mailbox y la dirección", errSyscallInvalida)
+		}
+
+		// RECV escribe en la memoria del proceso, así que solo se atiende cuando ya devolvió la CPU
+		if !syscall.conContexto {
+			return errDebeBloquearse
+		}
+
+		direccion, errDireccion := strconv.Atoi(syscall.Args[1])
+		if errDireccion != nil || direccion < 0 {
+			h.recursoInexistente(syscall)
+			return nil
+		}
+
+		err = h.Planificador.Recibir(syscall.PID, syscall.Args[0], direccion)
+		if errors.Is(err, planificadores.ErrMailboxAjeno) {
+			h.recursoInexistente(syscall)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error al bloquear proceso por RECV: %w", err)
+		}
+
 	case "EXIT":
 
 		go h.Planificador.FinalizarProcesoEnCualquierCola(syscall.PID)
 
 	default:
-		w.WriteHeader(http.StatusBadRequest)
-		_, _ = w.Write([]byte("Instrucción no reconocida"))
-		return
+		return fmt.Errorf("%w: instrucción no reconocida %s", errSyscallInvalida, syscall.Instruccion)
 	}
 
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte("ok"))
+	return nil
+
+}
+
+// esperarRecurso atiende WAIT <recurso>. Mientras el proceso sigue ejecutando solo se toma una instancia libre; si no
+// hay, la CPU devuelve el contexto y recién ahí se bloquea al proceso en la cola del recurso.
+func (h *Handler) esperarRecurso(syscall rtaCPU) error {
+	recurso := syscall.Args[0]
+
+	if syscall.conContexto {
+		if err := h.Planificador.EsperarRecurso(syscall.PID, recurso); err != nil {
+			return fmt.Errorf("error al bloquear proceso por recurso: %w", err)
+		}
+		return nil
+	}
+
+	tomado, err := h.Planificador.TomarRecurso(syscall.PID, recurso)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errSyscallInvalida, err)
+	}
+	if !tomado {
+		return errDebeBloquearse
+	}
+
+	return nil
+}
+
+// recursoInexistente manda a EXIT a un proceso que usó un recurso que no está en la configuración, o un mailbox o una
+// dirección que no puede usar. Como ya devolvió la CPU, no puede continuar.
+func (h *Handler) recursoInexistente(syscall rtaCPU) {
+	h.Log.Warn("Syscall sobre un recurso inválido, se finaliza el proceso",
+		log.IntAttr("pid", syscall.PID),
+		log.StringAttr("syscall", syscall.Instruccion),
+		log.StringAttr("recurso", syscall.Args[0]),
+	)
+
+	go h.Planificador.FinalizarProcesoEnCualquierCola(syscall.PID)
 }
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// Timeline devuelve los cambios de estado registrados por el planificador. El parámetro formato elige la salida:
+// json (por defecto), svg o ascii (diagrama de Gantt con una fila por CPU y por dispositivo IO).
+func (h *Handler) Timeline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	timeline := h.Planificador.Timeline
+
+	switch formato := r.URL.Query().Get("formato"); formato {
+	case "", "json":
+		contenido, err := timeline.JSON()
+		if err != nil {
+			h.Log.ErrorContext(ctx, "Error al serializar el timeline",
+				log.ErrAttr(err),
+			)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("Error al serializar el timeline"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(contenido)
+	case "svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		_, _ = w.Write([]byte(timeline.GanttSVG()))
+	case "ascii":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(timeline.GanttASCII()))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Formato de timeline desconocido: " + formato))
+	}
+}
+
+// ExportarTimeline escribe el timeline en el directorio timeline_path de la configuración. Si no está configurado,
+// no hace nada.
+func (h *Handler) ExportarTimeline() {
+	if h.Config.TimelinePath == "" {
+		return
+	}
+
+	if err := h.Planificador.Timeline.Exportar(h.Config.TimelinePath); err != nil {
+		h.Log.Error("Error al exportar el timeline",
+			log.ErrAttr(err),
+			log.StringAttr("directorio", h.Config.TimelinePath),
+		)
+		return
+	}
+
+	h.Log.Info("Timeline exportado",
+		log.StringAttr("directorio", h.Config.TimelinePath),
+	)
+}
@@ -0,0 +1,198 @@
+package consola
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	teclaCtrlC     = 3
+	teclaCtrlD     = 4
+	teclaTab       = 9
+	teclaEnter     = 13
+	teclaNuevaLin  = 10
+	teclaBorrar    = 127
+	teclaBackspace = 8
+	teclaEscape    = 27
+)
+
+// ErrInterrumpida se devuelve cuando el usuario cierra la consola (Ctrl+C, Ctrl+D o fin de la entrada)
+var ErrInterrumpida = errors.New("consola interrumpida")
+
+// Consola lee comandos de la terminal con historial (flechas arriba y abajo) y autocompletado de comandos (Tab). Si
+// la entrada no es una terminal, lee línea por línea sin edición.
+type Consola struct {
+	prompt    string
+	comandos  []string
+	historial []string
+	entrada   *bufio.Reader
+	salida    io.Writer
+
+	mutex     sync.Mutex
+	restaurar func() // Devuelve la terminal a su modo original, nil si no se cambió
+}
+
+// New crea una consola que lee de la entrada estándar y autocompleta con los comandos dados
+func New(prompt string, comandos []string) *Consola {
+	ordenados := append([]string(nil), comandos...)
+	sort.Strings(ordenados)
+
+	return &Consola{
+		prompt:   prompt,
+		comandos: ordenados,
+		entrada:  bufio.NewReader(os.Stdin),
+		salida:   os.Stdout,
+	}
+}
+
+// LeerLinea muestra el prompt y devuelve la línea ingresada, sin espacios al principio ni al final
+func (c *Consola) LeerLinea() (string, error) {
+	restaurar, err := modoSinEco(int(os.Stdin.Fd()))
+	if err != nil {
+		// No es una terminal: lectura simple
+		_, _ = fmt.Fprint(c.salida, c.prompt)
+		linea, err := c.entrada.ReadString('\n')
+		if err != nil && linea == "" {
+			return "", ErrInterrumpida
+		}
+		return strings.TrimSpace(linea), nil
+	}
+
+	c.mutex.Lock()
+	c.restaurar = restaurar
+	c.mutex.Unlock()
+	defer c.Restaurar()
+
+	linea, err := c.editar()
+	_, _ = fmt.Fprint(c.salida, "\n")
+	if err != nil {
+		return "", err
+	}
+
+	linea = strings.TrimSpace(linea)
+	if linea != "" && (len(c.historial) == 0 || c.historial[len(c.historial)-1] != linea) {
+		c.historial = append(c.historial, linea)
+	}
+
+	return linea, nil
+}
+
+// Restaurar devuelve la terminal a su modo original. Debe llamarse antes de terminar el programa mientras se está
+// leyendo una línea.
+func (c *Consola) Restaurar() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.restaurar != nil {
+		c.restaurar()
+		c.restaurar = nil
+	}
+}
+
+// editar lee tecla por tecla hasta el Enter, permitiendo borrar, recorrer el historial y autocompletar
+func (c *Consola) editar() (string, error) {
+	var (
+		buffer    []rune
+		posicion  = len(c.historial) // Posición en el historial, len(historial) es la línea nueva
+		enEdicion []rune             // Línea nueva guardada mientras se recorre el historial
+	)
+
+	c.redibujar(buffer)
+	for {
+		tecla, _, err := c.entrada.ReadRune()
+		if err != nil {
+			return "", ErrInterrumpida
+		}
+
+		switch tecla {
+		case teclaEnter, teclaNuevaLin:
+			return string(buffer), nil
+		case teclaCtrlC:
+			return "", ErrInterrumpida
+		case teclaCtrlD:
+			if len(buffer) == 0 {
+				return "", ErrInterrumpida
+			}
+		case teclaBorrar, teclaBackspace:
+			if len(buffer) > 0 {
+				buffer = buffer[:len(buffer)-1]
+			}
+		case teclaTab:
+			buffer = c.completar(buffer)
+		case teclaEscape:
+			// Secuencias de las flechas: ESC [ A (arriba) y ESC [ B (abajo). El resto se ignora.
+			if siguiente, _, _ := c.entrada.ReadRune(); siguiente != '[' {
+				continue
+			}
+			flecha, _, _ := c.entrada.ReadRune()
+			switch {
+			case flecha == 'A' && posicion > 0:
+				if posicion == len(c.historial) {
+					enEdicion = buffer
+				}
+				posicion--
+				buffer = []rune(c.historial[posicion])
+			case flecha == 'B' && posicion < len(c.historial):
+				posicion++
+				if posicion == len(c.historial) {
+					buffer = enEdicion
+				} else {
+					buffer = []rune(c.historial[posicion])
+				}
+			}
+		default:
+			if tecla >= ' ' {
+				buffer = append(buffer, tecla)
+			}
+		}
+
+		c.redibujar(buffer)
+	}
+}
+
+// completar autocompleta el nombre del comando (la primera palabra). Si hay una única opción la completa; si hay
+// varias, completa el prefijo común y muestra las opciones.
+func (c *Consola) completar(buffer []rune) []rune {
+	linea := string(buffer)
+	if strings.Contains(linea, " ") {
+		return buffer
+	}
+
+	opciones := make([]string, 0)
+	for _, comando := range c.comandos {
+		if strings.HasPrefix(comando, strings.ToUpper(linea)) {
+			opciones = append(opciones, comando)
+		}
+	}
+
+	switch len(opciones) {
+	case 0:
+		return buffer
+	case 1:
+		return []rune(opciones[0] + " ")
+	}
+
+	_, _ = fmt.Fprintf(c.salida, "\n%s\n", strings.Join(opciones, "  "))
+	return []rune(prefijoComun(opciones))
+}
+
+func (c *Consola) redibujar(buffer []rune) {
+	_, _ = fmt.Fprintf(c.salida, "\r\033[K%s%s", c.prompt, string(buffer))
+}
+
+func prefijoComun(opciones []string) string {
+	prefijo := opciones[0]
+	for _, opcion := range opciones[1:] {
+		for !strings.HasPrefix(opcion, prefijo) {
+			prefijo = prefijo[:len(prefijo)-1]
+		}
+	}
+
+	return prefijo
+}
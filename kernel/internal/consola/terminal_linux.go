@@ -0,0 +1,38 @@
+//go:build linux
+
+package consola
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// modoSinEco desactiva el modo canónico y el eco de la terminal para poder leer tecla por tecla. Las señales
+// (Ctrl+C) se siguen generando. Devuelve la función que restaura el modo original, o un error si fd no es una
+// terminal.
+func modoSinEco(fd int) (func(), error) {
+	var original syscall.Termios
+	if err := ioctlTermios(fd, syscall.TCGETS, &original); err != nil {
+		return nil, err
+	}
+
+	nuevo := original
+	nuevo.Lflag &^= syscall.ICANON | syscall.ECHO
+	nuevo.Cc[syscall.VMIN] = 1
+	nuevo.Cc[syscall.VTIME] = 0
+	if err := ioctlTermios(fd, syscall.TCSETS, &nuevo); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = ioctlTermios(fd, syscall.TCSETS, &original)
+	}, nil
+}
+
+func ioctlTermios(fd int, pedido uintptr, termios *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), pedido, uintptr(unsafe.Pointer(termios))); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
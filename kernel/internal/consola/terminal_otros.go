@@ -0,0 +1,10 @@
+//go:build !linux
+
+package consola
+
+import "errors"
+
+// modoSinEco no está soportado fuera de Linux: la consola lee línea por línea, sin historial ni autocompletado
+func modoSinEco(int) (func(), error) {
+	return nil, errors.New("modo sin eco no soportado en este sistema operativo")
+}
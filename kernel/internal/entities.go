@@ -23,13 +23,28 @@ type EstadoTiempo struct {
 
 type PCB struct {
 	PID                int                      `json:"pid"`
+	PIDPadre           int                      `json:"pid_padre,omitempty"` // PID del proceso que lo creó con INIT_PROC
+	PIDRaiz            int                      `json:"pid_raiz,omitempty"`  // PID del primer proceso de su árbol
 	PC                 int                      `json:"pc"`
 	MetricasEstado     map[Estado]int           `json:"metricas_estado"`
 	MetricasTiempo     map[Estado]*EstadoTiempo `json:"metricas_tiempo"`
 	Tamanio            string                   `json:"tamanio"`
+	TamanioBytes       int                      `json:"tamanio_bytes"`                 // Tamanio ya convertido, para no parsearlo en cada admisión
+	MotivoFinalizacion string                   `json:"motivo_finalizacion,omitempty"` // Error por el que finalizó, por ejemplo SEGMENTATION_FAULT
 	NombreArchivo      string                   `json:"nombre_archivo"`
+	Instrucciones      int                      `json:"instrucciones,omitempty"`   // Instrucciones del archivo, para la admisión SJF
 	RafagaAnterior     *time.Duration           `json:"rafaga_anterior,omitempty"` // Tiempo real de la ráfaga anterior
 	EstimacionAnterior float64
+	QuantumRestante    int            `json:"quantum_restante,omitempty"`  // Quantum no consumido en ms (VRR)
+	NivelMLFQ          int            `json:"nivel_mlfq"`                  // Nivel de la cola multinivel (MLFQ)
+	RafagasPorNivel    map[int]int    `json:"rafagas_por_nivel,omitempty"` // Ráfagas ejecutadas en cada nivel (MLFQ)
+	Prioridad          int            `json:"prioridad"`                   // Prioridad asignada al crearlo, a menor número mayor prioridad
+	PrioridadEfectiva  int            `json:"prioridad_efectiva"`          // Prioridad con aging aplicado mientras espera en READY
+	AfinidadCPU        string         `json:"afinidad_cpu,omitempty"`      // ID de la última CPU en la que ejecutó
+	Migraciones        int            `json:"migraciones"`                 // Veces que ejecutó en una CPU distinta a la anterior
+	Tickets            int            `json:"tickets"`                     // Tickets para los algoritmos LOTTERY y STRIDE
+	Pase               float64        `json:"pase,omitempty"`              // Pase acumulado (STRIDE)
+	DispositivosIO     map[string]int `json:"dispositivos_io,omitempty"`   // Veces que usó cada dispositivo IO
 }
 
 type Proceso struct {
@@ -0,0 +1,213 @@
+package planificadores
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+const (
+	esperaLiberacionCPU   = 5 * time.Second       // Tiempo máximo que se espera a que la CPU devuelva un proceso interrumpido
+	sondeoLiberacionCPU   = 10 * time.Millisecond // Cada cuánto se verifica si la CPU ya devolvió el proceso
+	tipoInterrupcionAdmin = "Desalojo"
+)
+
+var (
+	ErrProcesoNoEncontrado = errors.New("proceso no encontrado")
+	ErrEstadoInvalido      = errors.New("el proceso no está en un estado válido para la operación")
+)
+
+// suspensionManual registra un proceso suspendido desde la API. Mientras esté registrado, el proceso queda retenido
+// en SUSP.BLOCKED aunque termine el evento por el que estaba bloqueado.
+type suspensionManual struct {
+	esperaEvento bool // Si el proceso todavía espera el fin de su IO
+}
+
+// ForzarFinalizacion finaliza un proceso desde cualquier estado. Si está en EXEC, primero interrumpe a la CPU que lo
+// ejecuta y espera a que se lo devuelva.
+func (p *Service) ForzarFinalizacion(pid int) error {
+	if proceso, _ := p.BuscarProcesoEnCualquierCola(pid); proceso == nil {
+		return fmt.Errorf("%w: PID %d", ErrProcesoNoEncontrado, pid)
+	}
+
+	p.Log.Info(fmt.Sprintf("## (%d) - Finalización forzada desde la API", pid))
+	return p.finalizarDesdeCualquierEstado(pid, "")
+}
+
+// finalizarDesdeCualquierEstado finaliza un proceso esté donde esté, interrumpiendo a la CPU si está en EXEC. Si el
+// motivo no es vacío, se registra como motivo de finalización una vez que ninguna CPU lo ejecuta.
+func (p *Service) finalizarDesdeCualquierEstado(pid int, motivo string) error {
+	if err := p.interrumpirYEsperarCPU(pid); err != nil {
+		return err
+	}
+
+	if proceso, _ := p.BuscarProcesoEnCualquierCola(pid); motivo != "" && proceso != nil && proceso.PCB != nil {
+		proceso.PCB.MotivoFinalizacion = motivo
+	}
+
+	p.mutexSuspBlockQueue.Lock()
+	delete(p.suspensionesManuales, pid)
+	p.mutexSuspBlockQueue.Unlock()
+
+	p.FinalizarProcesoEnCualquierCola(pid)
+
+	return nil
+}
+
+// SuspenderManual suspende un proceso en READY, EXEC o BLOCKED: lo pasa a SUSP.BLOCKED y le pide a Memoria que lo
+// swappee. El proceso queda retenido ahí hasta que se lo reanude con ReanudarManual.
+func (p *Service) SuspenderManual(pid int) error {
+	if err := p.interrumpirYEsperarCPU(pid); err != nil {
+		return err
+	}
+
+	// Se vuelve a buscar: mientras la CPU lo devolvía, el proceso pudo haberse bloqueado o vuelto a READY
+	proceso, estado := p.BuscarProcesoEnCualquierCola(pid)
+	if proceso == nil {
+		return fmt.Errorf("%w: PID %d", ErrProcesoNoEncontrado, pid)
+	}
+
+	switch estado {
+	case internal.EstadoReady:
+		p.mutexReadyQueue.Lock()
+		var removido bool
+		p.Planificador.ReadyQueue, removido = p.removerDeCola(pid, p.Planificador.ReadyQueue)
+		if removido {
+			p.sacarDeColasCPU(pid)
+		}
+		p.mutexReadyQueue.Unlock()
+		if !removido {
+			return fmt.Errorf("%w: PID %d ya no está en READY", ErrEstadoInvalido, pid)
+		}
+	case internal.EstadoExec:
+		if !p.sacarDeCola(pid, p.mutexExecQueue, &p.Planificador.ExecQueue) {
+			return fmt.Errorf("%w: PID %d ya no está en EXEC", ErrEstadoInvalido, pid)
+		}
+	case internal.EstadoBloqueado:
+		if ultimo, ok := p.Timeline.UltimoEvento(pid); ok && ultimo.Recurso == RecursoDumpMemory {
+			return fmt.Errorf("%w: PID %d está bloqueado por DUMP_MEMORY", ErrEstadoInvalido, pid)
+		}
+		p.mutexBlockQueue.Lock()
+		if p.entregando[pid] {
+			p.mutexBlockQueue.Unlock()
+			return fmt.Errorf("%w: PID %d está recibiendo un mensaje", ErrEstadoInvalido, pid)
+		}
+		var removido bool
+		p.Planificador.BlockQueue, removido = p.removerDeCola(pid, p.Planificador.BlockQueue)
+		p.mutexBlockQueue.Unlock()
+		if !removido {
+			return fmt.Errorf("%w: PID %d ya no está en BLOCKED", ErrEstadoInvalido, pid)
+		}
+		p.Temporizadores.Cancelar(pid)
+	default:
+		return fmt.Errorf("%w: PID %d está en %s", ErrEstadoInvalido, pid, estado)
+	}
+
+	if tiempo := proceso.PCB.MetricasTiempo[estado]; tiempo != nil {
+		tiempo.TiempoAcumulado += clock.Since(tiempo.TiempoInicio)
+	}
+
+	p.mutexSuspBlockQueue.Lock()
+	p.Planificador.SuspBlockQueue = append(p.Planificador.SuspBlockQueue, proceso)
+	p.suspensionesManuales[pid] = &suspensionManual{esperaEvento: estado == internal.EstadoBloqueado}
+	p.mutexSuspBlockQueue.Unlock()
+
+	if proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado] == nil {
+		proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado] = &internal.EstadoTiempo{}
+	}
+	proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado].TiempoInicio = clock.Now()
+	proceso.PCB.MetricasEstado[internal.EstadoSuspBloqueado]++
+
+	p.Log.Info(fmt.Sprintf("## (%d) - Suspendido desde la API", pid))
+	p.registrarCambioEstado(pid, estado, internal.EstadoSuspBloqueado, "")
+
+	p.avisarAMemoriaSwap(proceso)
+
+	// Se liberó memoria, intentar traer procesos desde SUSP.READY o NEW
+	p.CheckearEspacioEnMemoria()
+
+	return nil
+}
+
+// ReanudarManual libera un proceso suspendido con SuspenderManual. Si ya no espera ningún evento pasa a SUSP.READY;
+// si su IO todavía no terminó, queda en SUSP.BLOCKED y pasará a SUSP.READY cuando termine.
+func (p *Service) ReanudarManual(pid int) error {
+	p.mutexSuspBlockQueue.Lock()
+	suspension, ok := p.suspensionesManuales[pid]
+	delete(p.suspensionesManuales, pid)
+	p.mutexSuspBlockQueue.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: PID %d no fue suspendido desde la API", ErrEstadoInvalido, pid)
+	}
+
+	p.Log.Info(fmt.Sprintf("## (%d) - Reanudado desde la API", pid))
+
+	if suspension.esperaEvento {
+		return nil
+	}
+
+	proceso := p.BuscarProcesoEnCola(pid, "suspended_blocked")
+	if proceso == nil {
+		return fmt.Errorf("%w: PID %d", ErrProcesoNoEncontrado, pid)
+	}
+	p.ManejarFinIO(proceso)
+
+	return nil
+}
+
+// retenerSuspensionManual indica si un proceso que terminó su IO en SUSP.BLOCKED debe quedar retenido por haber sido
+// suspendido desde la API. Si es así, registra que ya no espera el evento.
+func (p *Service) retenerSuspensionManual(pid int) bool {
+	p.mutexSuspBlockQueue.Lock()
+	defer p.mutexSuspBlockQueue.Unlock()
+
+	suspension, ok := p.suspensionesManuales[pid]
+	if ok {
+		suspension.esperaEvento = false
+	}
+
+	return ok
+}
+
+// interrumpirYEsperarCPU interrumpe a la CPU que ejecuta el proceso, si hay alguna, y espera a que lo devuelva
+func (p *Service) interrumpirYEsperarCPU(pid int) error {
+	cpuEjecutando := p.buscarCPUPorPID(pid)
+	if cpuEjecutando == nil {
+		return nil
+	}
+
+	p.Log.Debug("Interrumpiendo CPU por operación administrativa",
+		log.IntAttr("pid", pid),
+		log.StringAttr("cpu_id", cpuEjecutando.ID),
+	)
+	if !cpuEjecutando.EnviarInterrupcion(tipoInterrupcionAdmin, false) {
+		return fmt.Errorf("no se pudo interrumpir a la CPU %s que ejecuta el PID %d", cpuEjecutando.ID, pid)
+	}
+
+	// La CPU se libera cuando devuelve el contexto, no depende del reloj de la planificación
+	limite := time.Now().Add(esperaLiberacionCPU)
+	for p.buscarCPUPorPID(pid) != nil {
+		if time.Now().After(limite) {
+			return fmt.Errorf("la CPU %s no devolvió el PID %d a tiempo", cpuEjecutando.ID, pid)
+		}
+		time.Sleep(sondeoLiberacionCPU)
+	}
+
+	return nil
+}
+
+// sacarDeCola remueve un proceso de la cola dada tomando su mutex
+func (p *Service) sacarDeCola(pid int, mutex *sync.RWMutex, cola *[]*internal.Proceso) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	var removido bool
+	*cola, removido = p.removerDeCola(pid, *cola)
+	return removido
+}
@@ -0,0 +1,205 @@
+package planificadores
+
+import (
+	"math"
+	"sync"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// PoliticaAdmision es una política de planificación de largo plazo: decide cuál de los procesos de NEW es el próximo
+// en intentar entrar a memoria. El Service se encarga de consultar el espacio en memoria y de mover el proceso a
+// READY. Si el proceso elegido no entra, no se admite ningún otro hasta que se libere memoria.
+type PoliticaAdmision interface {
+	// ElegirProximo devuelve el índice en NewQueue del próximo proceso a admitir.
+	// IMPORTANTE: Se llama con el mutex de NewQueue bloqueado y la cola no vacía
+	ElegirProximo(nuevos []*internal.Proceso) int
+}
+
+// PoliticaAdmisionConMemoria lo implementan las políticas que necesitan datos de memoria para elegir. Los consultan
+// en estos métodos, que se llaman sin ninguna cola bloqueada, y no en ElegirProximo.
+type PoliticaAdmisionConMemoria interface {
+	// AlCrear se llama una vez por proceso, al crearlo y antes de que entre a NEW
+	AlCrear(proceso *internal.Proceso)
+
+	// AntesDeAdmitir se llama en cada intento de admisión, solo si el grado de multiprogramación permite admitir
+	AntesDeAdmitir()
+}
+
+// ConstructorPoliticaAdmision crea una instancia de una PoliticaAdmision para el Service dado
+type ConstructorPoliticaAdmision func(p *Service) PoliticaAdmision
+
+var (
+	registroPoliticasAdmision      = make(map[string]ConstructorPoliticaAdmision)
+	mutexRegistroPoliticasAdmision sync.RWMutex
+)
+
+// RegistrarPoliticaAdmision agrega una política de largo plazo al registro, para que pueda elegirse por nombre desde
+// el campo ready_ingress_algorithm de la configuración. Si ya existía una con el mismo nombre, se reemplaza.
+func RegistrarPoliticaAdmision(nombre string, constructor ConstructorPoliticaAdmision) {
+	mutexRegistroPoliticasAdmision.Lock()
+	defer mutexRegistroPoliticasAdmision.Unlock()
+
+	registroPoliticasAdmision[nombre] = constructor
+}
+
+func init() {
+	RegistrarPoliticaAdmision("FIFO", func(p *Service) PoliticaAdmision { return admisionFIFO{} })
+	RegistrarPoliticaAdmision("PMCP", func(p *Service) PoliticaAdmision { return admisionPorTamanio{} })
+	RegistrarPoliticaAdmision("PMGP", func(p *Service) PoliticaAdmision {
+		return admisionPorTamanio{masGrandePrimero: true}
+	})
+	RegistrarPoliticaAdmision("BEST_FIT", func(p *Service) PoliticaAdmision { return &admisionBestFit{service: p} })
+	RegistrarPoliticaAdmision("SJF", func(p *Service) PoliticaAdmision { return &admisionSJF{service: p} })
+}
+
+// nuevaPoliticaAdmision crea la PoliticaAdmision registrada con el nombre dado. Si no existe, se usa FIFO.
+func (p *Service) nuevaPoliticaAdmision(nombre string) PoliticaAdmision {
+	mutexRegistroPoliticasAdmision.RLock()
+	constructor, ok := registroPoliticasAdmision[nombre]
+	mutexRegistroPoliticasAdmision.RUnlock()
+
+	if !ok {
+		p.Log.Warn("Algoritmo de largo plazo no reconocido, se usará FIFO",
+			log.StringAttr("algoritmo", nombre),
+		)
+		return admisionFIFO{}
+	}
+
+	return constructor(p)
+}
+
+// admisionFIFO admite los procesos en orden de llegada
+type admisionFIFO struct{}
+
+func (admisionFIFO) ElegirProximo([]*internal.Proceso) int {
+	return 0
+}
+
+// admisionPorTamanio admite primero al proceso más chico (PMCP) o al más grande (PMGP). A igual tamaño, respeta el
+// orden de llegada.
+type admisionPorTamanio struct {
+	masGrandePrimero bool
+}
+
+func (a admisionPorTamanio) ElegirProximo(nuevos []*internal.Proceso) int {
+	elegido := 0
+	for i, proceso := range nuevos {
+		tamanio, tamanioElegido := proceso.PCB.TamanioBytes, nuevos[elegido].PCB.TamanioBytes
+		if (a.masGrandePrimero && tamanio > tamanioElegido) || (!a.masGrandePrimero && tamanio < tamanioElegido) {
+			elegido = i
+		}
+	}
+
+	return elegido
+}
+
+// admisionBestFit admite al proceso que mejor llena los marcos libres de memoria: el que más páginas necesita entre
+// los que entran. Si ninguno entra, elige al más chico (que tampoco se podrá admitir hasta que se libere memoria).
+// Los marcos libres se consultan una vez por intento de admisión, y se descuentan las páginas de cada proceso elegido.
+type admisionBestFit struct {
+	service       *Service
+	mutex         sync.Mutex
+	marcosLibres  int
+	tamanioPagina int // 0 si no se pudieron consultar los marcos libres
+}
+
+func (a *admisionBestFit) AlCrear(*internal.Proceso) {}
+
+func (a *admisionBestFit) AntesDeAdmitir() {
+	marcosLibres, tamanioPagina, err := a.service.Memoria.MarcosLibres()
+	if err != nil || tamanioPagina <= 0 {
+		a.service.Log.Warn("No se pudieron consultar los marcos libres, se admite por orden de llegada",
+			log.ErrAttr(err),
+		)
+		tamanioPagina = 0
+	}
+
+	a.mutex.Lock()
+	a.marcosLibres, a.tamanioPagina = marcosLibres, tamanioPagina
+	a.mutex.Unlock()
+}
+
+func (a *admisionBestFit) ElegirProximo(nuevos []*internal.Proceso) int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	marcosLibres, tamanioPagina := a.marcosLibres, a.tamanioPagina
+	if tamanioPagina <= 0 {
+		return 0
+	}
+
+	mejor, masChico := -1, 0
+	for i, proceso := range nuevos {
+		paginas := paginasNecesarias(proceso.PCB.TamanioBytes, tamanioPagina)
+
+		if paginas <= marcosLibres && (mejor == -1 || paginas > paginasNecesarias(nuevos[mejor].PCB.TamanioBytes, tamanioPagina)) {
+			mejor = i
+		}
+		if proceso.PCB.TamanioBytes < nuevos[masChico].PCB.TamanioBytes {
+			masChico = i
+		}
+	}
+
+	a.service.Log.Debug("Admisión BEST_FIT",
+		log.IntAttr("marcos_libres", marcosLibres),
+		log.IntAttr("elegido", mejor),
+	)
+
+	if mejor == -1 {
+		return masChico
+	}
+
+	// El proceso elegido entra, así que se admite: el próximo del mismo intento elige con los marcos que quedan
+	a.marcosLibres -= paginasNecesarias(nuevos[mejor].PCB.TamanioBytes, tamanioPagina)
+	return mejor
+}
+
+// admisionSJF admite primero al proceso con menos instrucciones, usando la cantidad de instrucciones de su archivo de
+// pseudocódigo como estimación de su duración. A igual estimación, respeta el orden de llegada.
+type admisionSJF struct {
+	service *Service
+}
+
+// AlCrear consulta a memoria la cantidad de instrucciones del proceso y la guarda en el PCB
+func (a *admisionSJF) AlCrear(proceso *internal.Proceso) {
+	cantidad, err := a.service.Memoria.CantidadInstrucciones(proceso.PCB.NombreArchivo)
+	if err != nil {
+		a.service.Log.Warn("No se pudo estimar la duración del proceso",
+			log.ErrAttr(err),
+			log.IntAttr("pid", proceso.PCB.PID),
+		)
+		return
+	}
+
+	proceso.PCB.Instrucciones = cantidad
+}
+
+func (a *admisionSJF) AntesDeAdmitir() {}
+
+func (a *admisionSJF) ElegirProximo(nuevos []*internal.Proceso) int {
+	elegido, estimacionElegido := 0, math.MaxInt
+	for i, proceso := range nuevos {
+		if estimacion := estimacionAdmision(proceso); estimacion < estimacionElegido {
+			elegido, estimacionElegido = i, estimacion
+		}
+	}
+
+	return elegido
+}
+
+// estimacionAdmision devuelve la cantidad de instrucciones del proceso. Si memoria no pudo responder al crearlo, el
+// proceso queda último.
+func estimacionAdmision(proceso *internal.Proceso) int {
+	if proceso.PCB.Instrucciones <= 0 {
+		return math.MaxInt
+	}
+
+	return proceso.PCB.Instrucciones
+}
+
+// paginasNecesarias devuelve las páginas que ocupa un proceso del tamaño dado, redondeando hacia arriba
+func paginasNecesarias(tamanio, tamanioPagina int) int {
+	return (tamanio + tamanioPagina - 1) / tamanioPagina
+}
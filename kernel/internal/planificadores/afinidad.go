@@ -0,0 +1,213 @@
+package planificadores
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/kernel/pkg/cpu"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// Con colas por CPU, cada proceso en READY se encola en la cola de la última CPU en la que ejecutó (su afinidad), para
+// aprovechar la TLB y la caché que quedaron cargadas. Los procesos que todavía no ejecutaron se encolan en la CPU con
+// menos procesos. Cada cola tiene su propio mutex, así elegir el próximo proceso de una CPU no bloquea ReadyQueue ni
+// las colas de las demás. Cuando una CPU se libera y su cola está vacía, le roba un proceso a la cola más cargada
+// (work stealing) y el proceso migra.
+// ReadyQueue sigue teniendo a todos los procesos en READY, para las consultas, las operaciones administrativas y el
+// desalojo.
+
+// colaSinCPU es la cola de los procesos que entran a READY antes de que se conecte alguna CPU
+const colaSinCPU = ""
+
+// colaCPU es la cola de READY de una CPU
+type colaCPU struct {
+	mutex    *sync.Mutex
+	procesos []*internal.Proceso
+}
+
+// elegirProcesoParaCPU devuelve el próximo proceso que debe ejecutar la CPU dada, o nil si no hay procesos en READY.
+// Sin colas por CPU, es el que elija el Scheduler de toda la ReadyQueue. El proceso sigue en su cola: lo saca
+// asignarProcesoACPU junto con ReadyQueue, así no queda en una cola sola si la asignación falla.
+func (p *Service) elegirProcesoParaCPU(cpuLibre *cpu.Cpu) *internal.Proceso {
+	if !p.ColasPorCPU {
+		p.mutexReadyQueue.Lock()
+		defer p.mutexReadyQueue.Unlock()
+
+		if len(p.Planificador.ReadyQueue) == 0 {
+			return nil
+		}
+		return p.Scheduler.ElegirProximo(p.Planificador.ReadyQueue)
+	}
+
+	if proceso := p.elegirDeCola(p.colaDeCPU(cpuLibre.ID)); proceso != nil {
+		return proceso
+	}
+
+	// Work stealing: la cola propia está vacía, se toma un proceso de la cola más cargada
+	victima, cola := p.colaMasCargada(cpuLibre.ID)
+	if cola == nil {
+		return nil
+	}
+
+	proceso := p.elegirDeCola(cola)
+	if proceso != nil {
+		p.Log.Debug("Work stealing: la CPU toma un proceso de otra cola",
+			log.StringAttr("cpu_id", cpuLibre.ID),
+			log.StringAttr("cpu_victima", victima),
+			log.IntAttr("pid", proceso.PCB.PID),
+		)
+	}
+
+	return proceso
+}
+
+// elegirDeCola devuelve el proceso de la cola que elija el Scheduler, o nil si está vacía
+func (p *Service) elegirDeCola(cola *colaCPU) *internal.Proceso {
+	cola.mutex.Lock()
+	defer cola.mutex.Unlock()
+
+	if len(cola.procesos) == 0 {
+		return nil
+	}
+
+	return p.Scheduler.ElegirProximo(cola.procesos)
+}
+
+// colaMasCargada devuelve la cola con más procesos, sin contar la de la CPU dada, o nil si están todas vacías. Entre
+// colas con la misma cantidad se elige la de menor ID, para que el robo sea determinístico.
+func (p *Service) colaMasCargada(excluida string) (string, *colaCPU) {
+	p.mutexColasCPU.RLock()
+	defer p.mutexColasCPU.RUnlock()
+
+	var victima string
+	var elegida *colaCPU
+	var maximo int
+	for cpuID, cola := range p.colasCPU {
+		if cpuID == excluida {
+			continue
+		}
+
+		cola.mutex.Lock()
+		cantidad := len(cola.procesos)
+		cola.mutex.Unlock()
+
+		if cantidad > maximo || (cantidad == maximo && cantidad > 0 && cpuID < victima) {
+			victima, elegida, maximo = cpuID, cola, cantidad
+		}
+	}
+
+	return victima, elegida
+}
+
+// colaDeCPU devuelve la cola de la CPU, creándola si todavía no existe
+func (p *Service) colaDeCPU(cpuID string) *colaCPU {
+	p.mutexColasCPU.RLock()
+	cola, ok := p.colasCPU[cpuID]
+	p.mutexColasCPU.RUnlock()
+	if ok {
+		return cola
+	}
+
+	p.mutexColasCPU.Lock()
+	defer p.mutexColasCPU.Unlock()
+
+	if cola, ok = p.colasCPU[cpuID]; !ok {
+		cola = &colaCPU{mutex: &sync.Mutex{}}
+		p.colasCPU[cpuID] = cola
+	}
+	return cola
+}
+
+// encolarEnCPU agrega el proceso que entra a READY a la cola de su CPU afín o, si no tiene afinidad, a la de la CPU
+// con menos procesos
+func (p *Service) encolarEnCPU(proceso *internal.Proceso) {
+	var cola *colaCPU
+	if proceso.PCB.AfinidadCPU != "" {
+		cola = p.colaDeCPU(proceso.PCB.AfinidadCPU)
+	} else {
+		cola = p.colaMenosCargada()
+	}
+
+	cola.mutex.Lock()
+	cola.procesos = append(cola.procesos, proceso)
+	cola.mutex.Unlock()
+
+	p.mutexColasCPU.Lock()
+	p.colaDeProceso[proceso.PCB.PID] = cola
+	p.mutexColasCPU.Unlock()
+}
+
+// colaMenosCargada devuelve la cola de CPU con menos procesos, o la de los procesos sin CPU si no se conectó ninguna
+func (p *Service) colaMenosCargada() *colaCPU {
+	p.mutexColasCPU.RLock()
+	var elegida *colaCPU
+	var elegidaID string
+	minimo := -1
+	for cpuID, cola := range p.colasCPU {
+		if cpuID == colaSinCPU {
+			continue
+		}
+
+		cola.mutex.Lock()
+		cantidad := len(cola.procesos)
+		cola.mutex.Unlock()
+
+		if minimo == -1 || cantidad < minimo || (cantidad == minimo && cpuID < elegidaID) {
+			elegida, elegidaID, minimo = cola, cpuID, cantidad
+		}
+	}
+	p.mutexColasCPU.RUnlock()
+
+	if elegida == nil {
+		return p.colaDeCPU(colaSinCPU)
+	}
+	return elegida
+}
+
+// sacarDeColasCPU saca al proceso de la cola de CPU en la que esté, cuando deja READY
+// IMPORTANTE: Se llama con el mutex de ReadyQueue bloqueado, en el mismo paso que lo saca de ReadyQueue
+func (p *Service) sacarDeColasCPU(pid int) {
+	if !p.ColasPorCPU {
+		return
+	}
+
+	p.mutexColasCPU.Lock()
+	cola, ok := p.colaDeProceso[pid]
+	delete(p.colaDeProceso, pid)
+	p.mutexColasCPU.Unlock()
+	if !ok {
+		return
+	}
+
+	cola.mutex.Lock()
+	cola.procesos, _ = p.removerDeCola(pid, cola.procesos)
+	cola.mutex.Unlock()
+}
+
+// registrarAfinidad actualiza la afinidad del proceso que pasa a ejecutar en la CPU dada y, si venía de otra CPU,
+// cuenta la migración.
+func (p *Service) registrarAfinidad(proceso *internal.Proceso, cpuAsignada *cpu.Cpu) {
+	if proceso.PCB.AfinidadCPU != "" && proceso.PCB.AfinidadCPU != cpuAsignada.ID {
+		proceso.PCB.Migraciones++
+
+		p.Log.Debug("Proceso migra de CPU",
+			log.IntAttr("pid", proceso.PCB.PID),
+			log.StringAttr("cpu_anterior", proceso.PCB.AfinidadCPU),
+			log.StringAttr("cpu_nueva", cpuAsignada.ID),
+			log.IntAttr("migraciones", proceso.PCB.Migraciones),
+		)
+	}
+
+	proceso.PCB.AfinidadCPU = cpuAsignada.ID
+}
+
+// metricasAfinidad devuelve la cantidad de migraciones del proceso para agregar al log de métricas de estado.
+// Devuelve un string vacío si no se usan colas por CPU.
+func (p *Service) metricasAfinidad(proceso *internal.Proceso) string {
+	if !p.ColasPorCPU {
+		return ""
+	}
+
+	return fmt.Sprintf(", MIGRACIONES %d", proceso.PCB.Migraciones)
+}
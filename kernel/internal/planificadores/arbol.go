@@ -0,0 +1,225 @@
+package planificadores
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+const (
+	RecursoWait       = "WAIT" // Recurso de los bloqueos por WAIT, que no usan un dispositivo IO
+	EsperarCualquiera = -1     // Objetivo de WAIT ANY: el padre espera a que finalice cualquiera de sus hijos
+
+	PoliticaHijosHuerfanos = "ORPHAN"  // Los hijos de un proceso que finaliza pasan a ser hijos de su abuelo
+	PoliticaHijosCascada   = "CASCADE" // Los hijos de un proceso que finaliza también finalizan
+
+	MotivoFinalizacionCascada = "PARENT_EXIT" // Motivo de los procesos finalizados por la política CASCADE
+)
+
+// arbolProcesos registra qué procesos creó cada proceso con INIT_PROC y qué padres están bloqueados por WAIT. Solo
+// guarda procesos vivos: al finalizar, un proceso sale del árbol.
+// Orden de los mutex: el del árbol se toma antes que los de las colas.
+type arbolProcesos struct {
+	mutex   sync.Mutex
+	padres  map[int]int              // PID → PID de su padre, para los procesos creados con INIT_PROC
+	hijos   map[int]map[int]struct{} // PID → PIDs de sus hijos vivos
+	esperas map[int]int              // PID de un padre bloqueado por WAIT → PID que espera, o EsperarCualquiera
+}
+
+func newArbolProcesos() *arbolProcesos {
+	return &arbolProcesos{
+		padres:  make(map[int]int),
+		hijos:   make(map[int]map[int]struct{}),
+		esperas: make(map[int]int),
+	}
+}
+
+// agregarHijo registra el vínculo entre padre e hijo
+// IMPORTANTE: Se llama con el mutex del árbol bloqueado
+func (a *arbolProcesos) agregarHijo(padre, hijo int) {
+	a.padres[hijo] = padre
+	if a.hijos[padre] == nil {
+		a.hijos[padre] = make(map[int]struct{})
+	}
+	a.hijos[padre][hijo] = struct{}{}
+}
+
+// quitarHijo borra el vínculo del proceso con su padre, si lo tiene
+// IMPORTANTE: Se llama con el mutex del árbol bloqueado
+func (a *arbolProcesos) quitarHijo(hijo int) {
+	padre, ok := a.padres[hijo]
+	if !ok {
+		return
+	}
+
+	delete(a.padres, hijo)
+	delete(a.hijos[padre], hijo)
+	if len(a.hijos[padre]) == 0 {
+		delete(a.hijos, padre)
+	}
+}
+
+// hijosOrdenados devuelve los hijos vivos del proceso, ordenados por PID
+// IMPORTANTE: Se llama con el mutex del árbol bloqueado
+func (a *arbolProcesos) hijosOrdenados(pid int) []int {
+	hijos := make([]int, 0, len(a.hijos[pid]))
+	for hijo := range a.hijos[pid] {
+		hijos = append(hijos, hijo)
+	}
+	sort.Ints(hijos)
+
+	return hijos
+}
+
+// UsarPoliticaHijos elige qué pasa con los hijos de un proceso que finaliza (children_policy). Sin nombre, o si no
+// existe, quedan huérfanos y pasan a su abuelo.
+func (p *Service) UsarPoliticaHijos(nombre string) {
+	nombre = strings.ToUpper(nombre)
+	switch nombre {
+	case PoliticaHijosHuerfanos, PoliticaHijosCascada:
+	case "":
+		nombre = PoliticaHijosHuerfanos
+	default:
+		p.Log.Warn("Política de hijos no reconocida, se usará ORPHAN",
+			log.StringAttr("politica", nombre),
+		)
+		nombre = PoliticaHijosHuerfanos
+	}
+
+	p.PoliticaHijos = nombre
+}
+
+// RegistrarHijo vincula un proceso creado con INIT_PROC con el proceso que lo creó. El hijo hereda la raíz del
+// árbol del padre, para poder agrupar a todos los procesos de una misma corrida.
+// IMPORTANTE: Se llama antes de que el hijo ingrese a NEW
+func (p *Service) RegistrarHijo(pidPadre int, hijo *internal.Proceso) {
+	hijo.PCB.PIDPadre = pidPadre
+	hijo.PCB.PIDRaiz = pidPadre
+	if padre, _ := p.BuscarProcesoEnCualquierCola(pidPadre); padre != nil && padre.PCB != nil && padre.PCB.PIDRaiz != 0 {
+		hijo.PCB.PIDRaiz = padre.PCB.PIDRaiz
+	}
+
+	p.arbol.mutex.Lock()
+	p.arbol.agregarHijo(pidPadre, hijo.PCB.PID)
+	p.arbol.mutex.Unlock()
+}
+
+// Esperar atiende la syscall WAIT: bloquea al proceso hasta que finalice el hijo indicado, o cualquiera de sus hijos
+// con EsperarCualquiera. Si no tiene un hijo vivo que esperar, se desbloquea en el momento.
+func (p *Service) Esperar(pid, objetivo int) error {
+	// El árbol queda bloqueado hasta que el proceso esté en BLOCKED, para que ningún hijo lo despierte antes
+	p.arbol.mutex.Lock()
+	_, esHijo := p.arbol.hijos[pid][objetivo]
+	hayQueEsperar := esHijo || (objetivo == EsperarCualquiera && len(p.arbol.hijos[pid]) > 0)
+
+	proceso, err := p.bloquear(pid, RecursoWait, nil)
+	if err != nil {
+		p.arbol.mutex.Unlock()
+		return err
+	}
+	if hayQueEsperar {
+		p.arbol.esperas[pid] = objetivo
+	}
+	p.arbol.mutex.Unlock()
+
+	if !hayQueEsperar {
+		p.Log.Info(fmt.Sprintf("## (%d) - WAIT sin hijos que esperar, continúa", pid),
+			log.StringAttr("objetivo", descripcionObjetivo(objetivo)),
+		)
+		p.ManejarFinIO(proceso)
+		return nil
+	}
+
+	p.Log.Info(fmt.Sprintf("## (%d) - Bloqueado por WAIT: %s", pid, descripcionObjetivo(objetivo)))
+	return nil
+}
+
+func descripcionObjetivo(objetivo int) string {
+	if objetivo == EsperarCualquiera {
+		return "ANY"
+	}
+	return fmt.Sprint(objetivo)
+}
+
+// sacarDelArbol se llama cuando un proceso finaliza: despierta a su padre si lo esperaba y, según la política, deja
+// huérfanos a sus hijos o los finaliza
+func (p *Service) sacarDelArbol(pcb *internal.PCB) {
+	pid := pcb.PID
+
+	p.arbol.mutex.Lock()
+	padre, tienePadre := p.arbol.padres[pid]
+	p.arbol.quitarHijo(pid)
+	delete(p.arbol.esperas, pid)
+
+	var despertarPadre bool
+	if objetivo, espera := p.arbol.esperas[padre]; tienePadre && espera &&
+		(objetivo == pid || objetivo == EsperarCualquiera) {
+		delete(p.arbol.esperas, padre)
+		despertarPadre = true
+	}
+
+	// Los huérfanos pasan al abuelo solo si todavía no finalizó
+	hijos := p.arbol.hijosOrdenados(pid)
+	nuevoPadre := 0
+	if abuelo, _ := p.BuscarProcesoEnCualquierCola(padre); tienePadre && abuelo != nil {
+		nuevoPadre = padre
+	}
+	for _, hijo := range hijos {
+		p.arbol.quitarHijo(hijo)
+		if p.PoliticaHijos == PoliticaHijosHuerfanos && nuevoPadre != 0 {
+			p.arbol.agregarHijo(nuevoPadre, hijo)
+		}
+	}
+	p.arbol.mutex.Unlock()
+
+	if despertarPadre {
+		p.despertarPorWait(padre, pid)
+	}
+
+	for _, hijo := range hijos {
+		if p.PoliticaHijos == PoliticaHijosCascada {
+			go p.finalizarPorCascada(hijo, pid)
+			continue
+		}
+
+		if proceso, _ := p.BuscarProcesoEnCualquierCola(hijo); proceso != nil && proceso.PCB != nil {
+			proceso.PCB.PIDPadre = nuevoPadre
+		}
+		p.Log.Info(fmt.Sprintf("## (%d) - Queda huérfano, nuevo padre: %d", hijo, nuevoPadre),
+			log.IntAttr("pid_padre_anterior", pid),
+		)
+	}
+}
+
+// despertarPorWait desbloquea al padre que esperaba la finalización del hijo
+func (p *Service) despertarPorWait(padre, hijo int) {
+	proceso := p.BuscarProcesoEnCola(padre, "")
+	if proceso == nil {
+		p.Log.Debug("El proceso que esperaba por WAIT ya no está bloqueado",
+			log.IntAttr("pid", padre),
+			log.IntAttr("pid_hijo", hijo),
+		)
+		return
+	}
+
+	p.Log.Info(fmt.Sprintf("## (%d) - Finalizó el hijo %d, se desbloquea por WAIT", padre, hijo))
+	p.ManejarFinIO(proceso)
+}
+
+// finalizarPorCascada finaliza a un hijo cuyo padre finalizó con la política CASCADE. Sus propios hijos siguen la
+// misma política cuando él sale del árbol.
+func (p *Service) finalizarPorCascada(pid, padre int) {
+	p.Log.Info(fmt.Sprintf("## (%d) - Finaliza porque finalizó su padre %d", pid, padre))
+
+	if err := p.finalizarDesdeCualquierEstado(pid, MotivoFinalizacionCascada); err != nil {
+		p.Log.Error("Error al finalizar en cascada",
+			log.ErrAttr(err),
+			log.IntAttr("pid", pid),
+			log.IntAttr("pid_padre", padre),
+		)
+	}
+}
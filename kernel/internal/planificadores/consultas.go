@@ -0,0 +1,163 @@
+package planificadores
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+)
+
+// EstadoProceso es una foto de un proceso para las consultas de solo lectura: una copia de su PCB, el estado en el
+// que se encuentra y la estimación de su próxima ráfaga (SJF/SRT).
+type EstadoProceso struct {
+	PCB           internal.PCB    `json:"pcb"`
+	Estado        internal.Estado `json:"estado"`
+	EstimacionSJF float64         `json:"estimacion_sjf"`
+}
+
+// EstadoCPU es una foto de una CPU conectada
+type EstadoCPU struct {
+	ID     string `json:"id"`
+	IP     string `json:"ip"`
+	Puerto int    `json:"puerto"`
+	Libre  bool   `json:"libre"`
+	PID    int    `json:"pid"` // -1 si no está ejecutando ningún proceso
+}
+
+// colaConsultable asocia una cola del planificador con su estado y su mutex
+type colaConsultable struct {
+	estado internal.Estado
+	mutex  *sync.RWMutex
+	cola   *[]*internal.Proceso
+}
+
+// colasConsultables devuelve las colas de los procesos que todavía no finalizaron, en el orden del ciclo de vida
+func (p *Service) colasConsultables() []colaConsultable {
+	return []colaConsultable{
+		{internal.EstadoNew, p.mutexNewQueue, &p.Planificador.NewQueue},
+		{internal.EstadoReady, p.mutexReadyQueue, &p.Planificador.ReadyQueue},
+		{internal.EstadoExec, p.mutexExecQueue, &p.Planificador.ExecQueue},
+		{internal.EstadoBloqueado, p.mutexBlockQueue, &p.Planificador.BlockQueue},
+		{internal.EstadoSuspReady, p.mutexSuspReadyQueue, &p.Planificador.SuspReadyQueue},
+		{internal.EstadoSuspBloqueado, p.mutexSuspBlockQueue, &p.Planificador.SuspBlockQueue},
+	}
+}
+
+// Procesos devuelve una foto de todos los procesos que todavía no finalizaron, ordenados por PID
+func (p *Service) Procesos() []EstadoProceso {
+	procesos := make([]EstadoProceso, 0)
+
+	for _, c := range p.colasConsultables() {
+		c.mutex.RLock()
+		for _, proc := range *c.cola {
+			if proc != nil && proc.PCB != nil {
+				procesos = append(procesos, p.fotoProceso(proc, c.estado))
+			}
+		}
+		c.mutex.RUnlock()
+	}
+
+	sort.Slice(procesos, func(i, j int) bool {
+		return procesos[i].PCB.PID < procesos[j].PCB.PID
+	})
+
+	return procesos
+}
+
+// Proceso devuelve una foto del proceso con el PID dado. Devuelve false si no está en ninguna cola.
+func (p *Service) Proceso(pid int) (EstadoProceso, bool) {
+	for _, c := range p.colasConsultables() {
+		c.mutex.RLock()
+		for _, proc := range *c.cola {
+			if proc != nil && proc.PCB != nil && proc.PCB.PID == pid {
+				foto := p.fotoProceso(proc, c.estado)
+				c.mutex.RUnlock()
+				return foto, true
+			}
+		}
+		c.mutex.RUnlock()
+	}
+
+	return EstadoProceso{}, false
+}
+
+// Colas devuelve los PIDs de cada cola del planificador, en el orden en el que están encolados
+func (p *Service) Colas() map[internal.Estado][]int {
+	colas := make(map[internal.Estado][]int)
+
+	for _, c := range p.colasConsultables() {
+		c.mutex.RLock()
+		pids := make([]int, 0, len(*c.cola))
+		for _, proc := range *c.cola {
+			if proc != nil && proc.PCB != nil {
+				pids = append(pids, proc.PCB.PID)
+			}
+		}
+		c.mutex.RUnlock()
+
+		colas[c.estado] = pids
+	}
+
+	return colas
+}
+
+// CPUs devuelve una foto de las CPUs conectadas con el proceso que ejecuta cada una
+func (p *Service) CPUs() []EstadoCPU {
+	p.mutexCPUsConectadas.RLock()
+	defer p.mutexCPUsConectadas.RUnlock()
+
+	cpus := make([]EstadoCPU, 0, len(p.CPUsConectadas))
+	for _, c := range p.CPUsConectadas {
+		pid := -1
+		if !c.Estado && c.Proceso != nil {
+			pid = c.Proceso.PID
+		}
+
+		cpus = append(cpus, EstadoCPU{
+			ID:     c.ID,
+			IP:     c.IP,
+			Puerto: c.Puerto,
+			Libre:  c.Estado,
+			PID:    pid,
+		})
+	}
+
+	return cpus
+}
+
+// fotoProceso copia el PCB del proceso para que pueda serializarse sin el lock de su cola
+// IMPORTANTE: El mutex de la cola del proceso debe estar ya bloqueado por quien llama esta función
+func (p *Service) fotoProceso(proceso *internal.Proceso, estado internal.Estado) EstadoProceso {
+	pcb := *proceso.PCB
+
+	pcb.MetricasEstado = make(map[internal.Estado]int, len(proceso.PCB.MetricasEstado))
+	for e, cantidad := range proceso.PCB.MetricasEstado {
+		pcb.MetricasEstado[e] = cantidad
+	}
+
+	pcb.MetricasTiempo = make(map[internal.Estado]*internal.EstadoTiempo, len(proceso.PCB.MetricasTiempo))
+	for e, tiempo := range proceso.PCB.MetricasTiempo {
+		if tiempo != nil {
+			copia := *tiempo
+			pcb.MetricasTiempo[e] = &copia
+		}
+	}
+
+	if proceso.PCB.RafagasPorNivel != nil {
+		pcb.RafagasPorNivel = make(map[int]int, len(proceso.PCB.RafagasPorNivel))
+		for nivel, rafagas := range proceso.PCB.RafagasPorNivel {
+			pcb.RafagasPorNivel[nivel] = rafagas
+		}
+	}
+
+	var estimacion float64
+	if p.SjfConfig != nil {
+		estimacion = p.calcularSiguienteEstimacion(proceso)
+	}
+
+	return EstadoProceso{
+		PCB:           pcb,
+		Estado:        estado,
+		EstimacionSJF: estimacion,
+	}
+}
@@ -0,0 +1,137 @@
+package planificadores
+
+import (
+	"fmt"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// IniciarPlanificacion pone en marcha (o reanuda) los planificadores de largo y corto plazo
+func (p *Service) IniciarPlanificacion() {
+	p.mutexPlanificacion.Lock()
+	if p.EstadoPlanificacion == PlanificadorEstadoStart {
+		p.mutexPlanificacion.Unlock()
+		return
+	}
+	p.EstadoPlanificacion = PlanificadorEstadoStart
+	p.condPlanificacion.Broadcast()
+	p.mutexPlanificacion.Unlock()
+
+	p.Log.Info("Planificación iniciada")
+
+	// Los procesos que llegaron mientras estaba detenida esperan en NEW
+	p.CheckearEspacioEnMemoria()
+}
+
+// DetenerPlanificacion pausa los planificadores de largo y corto plazo. Los procesos en ejecución continúan hasta
+// que devuelvan la CPU, pero no se admiten procesos nuevos ni se despachan procesos de READY.
+func (p *Service) DetenerPlanificacion() {
+	p.mutexPlanificacion.Lock()
+	defer p.mutexPlanificacion.Unlock()
+
+	if p.EstadoPlanificacion == PlanificadorEstadoStop {
+		return
+	}
+	p.EstadoPlanificacion = PlanificadorEstadoStop
+
+	p.Log.Info("Planificación detenida")
+}
+
+// PlanificacionActiva indica si los planificadores están en marcha
+func (p *Service) PlanificacionActiva() bool {
+	p.mutexPlanificacion.Lock()
+	defer p.mutexPlanificacion.Unlock()
+
+	return p.EstadoPlanificacion == PlanificadorEstadoStart
+}
+
+// esperarPlanificacionActiva bloquea hasta que la planificación esté en marcha
+func (p *Service) esperarPlanificacionActiva() {
+	p.mutexPlanificacion.Lock()
+	defer p.mutexPlanificacion.Unlock()
+
+	for p.EstadoPlanificacion != PlanificadorEstadoStart {
+		p.condPlanificacion.Wait()
+	}
+}
+
+// CambiarGradoMultiprogramacion cambia la cantidad máxima de procesos en memoria (READY, EXEC y BLOCKED). Un valor
+// menor o igual a 0 quita el límite.
+func (p *Service) CambiarGradoMultiprogramacion(grado int) {
+	p.mutexPlanificacion.Lock()
+	anterior := p.GradoMultiprogramacion
+	p.GradoMultiprogramacion = grado
+	p.mutexPlanificacion.Unlock()
+
+	p.Log.Info("Grado de multiprogramación actualizado",
+		log.IntAttr("anterior", anterior),
+		log.IntAttr("nuevo", grado),
+	)
+
+	// Si el límite aumentó, puede haber lugar para procesos que esperaban
+	p.CheckearEspacioEnMemoria()
+}
+
+// EstadoMultiprogramacion es una foto del grado de multiprogramación
+type EstadoMultiprogramacion struct {
+	Grado     int `json:"grado"`      // 0 sin límite
+	EnMemoria int `json:"en_memoria"` // Procesos en READY, EXEC y BLOCKED
+	Esperando int `json:"esperando"`  // Procesos en NEW y SUSP.READY que no se admiten solo por el grado
+}
+
+// Multiprogramacion devuelve el grado de multiprogramación, los procesos en memoria y los que esperan por el grado
+func (p *Service) Multiprogramacion() EstadoMultiprogramacion {
+	p.mutexPlanificacion.Lock()
+	estado := EstadoMultiprogramacion{
+		Grado:     p.GradoMultiprogramacion,
+		Esperando: p.esperandoMultiprogramacion,
+	}
+	p.mutexPlanificacion.Unlock()
+
+	estado.EnMemoria = p.procesosEnMemoria()
+	return estado
+}
+
+// registrarEsperaMultiprogramacion guarda cuántos procesos esperan solo por el grado de multiprogramación y lo
+// informa cuando cambia
+func (p *Service) registrarEsperaMultiprogramacion(esperando int) {
+	p.mutexPlanificacion.Lock()
+	anterior := p.esperandoMultiprogramacion
+	p.esperandoMultiprogramacion = esperando
+	grado := p.GradoMultiprogramacion
+	p.mutexPlanificacion.Unlock()
+
+	if esperando != anterior {
+		p.Log.Info(fmt.Sprintf("## Procesos esperando por el grado de multiprogramación (%d): %d", grado, esperando))
+	}
+}
+
+// hayLugarEnMultiprogramacion indica si se puede admitir un proceso más sin superar el grado de multiprogramación
+func (p *Service) hayLugarEnMultiprogramacion() bool {
+	p.mutexPlanificacion.Lock()
+	grado := p.GradoMultiprogramacion
+	p.mutexPlanificacion.Unlock()
+
+	if grado <= 0 {
+		return true
+	}
+
+	return p.procesosEnMemoria() < grado
+}
+
+// procesosEnMemoria cuenta los procesos en READY, EXEC y BLOCKED
+func (p *Service) procesosEnMemoria() int {
+	cantidad := 0
+
+	for _, c := range p.colasConsultables() {
+		switch c.estado {
+		case internal.EstadoReady, internal.EstadoExec, internal.EstadoBloqueado:
+			c.mutex.RLock()
+			cantidad += len(*c.cola)
+			c.mutex.RUnlock()
+		}
+	}
+
+	return cantidad
+}
@@ -2,207 +2,40 @@ package planificadores
 
 import (
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/internal"
 	"github.com/sisoputnfrba/tp-golang/kernel/pkg/cpu"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
-func (p *Service) PlanificadorCortoPlazo() {
-	switch p.ShortTermAlgorithm {
-	case "FIFO":
-		go p.PlanificadorCortoPlazoFIFO()
-	case "SJF":
-		go p.PlanificarCortoPlazoSjfSinDesalojo()
-	case "SRT":
-		go p.PlanificarCortoPlazoSjfDesalojo()
-	default:
-		p.Log.Warn("Algoritmo de corto plazo no reconocido")
-	}
+// schedulerSJF elige al proceso que posea la ráfaga de CPU estimada más corta. Con desalojo (SRT), al ingresar un
+// proceso en la cola de Ready y no haber CPUs libres, se debe evaluar si dicho proceso tiene una rafaga más corta que
+// los que se encuentran en ejecución. En caso de ser así, se debe informar al CPU que posea al Proceso con el tiempo
+// restante más alto que debe desalojar al mismo para que pueda ser planificado el nuevo.
+type schedulerSJF struct {
+	service  *Service
+	desalojo bool
 }
 
-func (p *Service) PlanificadorCortoPlazoFIFO() {
-	for {
-		// Esperar hasta que haya trabajo que hacer
-		select {
-		case <-p.canalNuevoProcesoReady:
-			// Hay nuevos procesos en Ready, procesarlos
-			p.Log.Debug("Notificación de nuevo proceso en Ready recibida")
-		default:
-			// No hay notificaciones pendientes, verificar si hay procesos para procesar
-			if len(p.Planificador.ReadyQueue) == 0 {
-				// No hay trabajo que hacer, pausar para evitar espera activa
-				time.Sleep(10 * time.Millisecond)
-				continue
-			}
-		}
+// ElegirProximo ordena la cola de ReadyQueue por ráfaga estimada ascendente y devuelve el primero
+func (s *schedulerSJF) ElegirProximo(ready []*internal.Proceso) *internal.Proceso {
+	ordenarEstable(ready, func(a, b *internal.Proceso) bool {
+		return s.service.calcularSiguienteEstimacion(a) < s.service.calcularSiguienteEstimacion(b)
+	})
 
-		// Procesar todos los procesos en ReadyQueue
-		for len(p.Planificador.ReadyQueue) > 0 {
-			// Usar versión bloqueante para adquirir CPU
-			cpuLibre := p.BuscarCPUDisponible()
-
-			if cpuLibre != nil {
-				// Mover proceso de READY a EXEC
-				p.mutexReadyQueue.Lock()
-				procesoElegido := p.Planificador.ReadyQueue[0]
-				p.Planificador.ReadyQueue = p.Planificador.ReadyQueue[1:]
-				p.mutexReadyQueue.Unlock()
-
-				// Ejecutar proceso en rutina para permitir concurrencia
-				go func(cpuElegida *cpu.Cpu, proceso *internal.Proceso) {
-					// Actualizar métricas de tiempo
-					if proceso.PCB.MetricasTiempo[internal.EstadoReady] != nil {
-						proceso.PCB.MetricasTiempo[internal.EstadoReady].TiempoAcumulado +=
-							time.Since(proceso.PCB.MetricasTiempo[internal.EstadoReady].TiempoInicio)
-					}
-
-					p.mutexExecQueue.Lock()
-					p.Planificador.ExecQueue = append(p.Planificador.ExecQueue, proceso)
-
-					if proceso.PCB.MetricasTiempo[internal.EstadoExec] == nil {
-						proceso.PCB.MetricasTiempo[internal.EstadoExec] = &internal.EstadoTiempo{}
-					}
-					proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio = time.Now()
-					proceso.PCB.MetricasEstado[internal.EstadoExec]++
-					p.mutexExecQueue.Unlock()
-
-					//Log obligatorio: Cambio de estado
-					// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-					p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado READY al estado EXEC", proceso.PCB.PID))
-
-					// Usar los valores copiados
-					cpuElegida.Proceso.PC = procesoElegido.PCB.PC
-					cpuElegida.Proceso.PID = procesoElegido.PCB.PID
-
-					p.Log.Debug("CPU seleccionada para proceso",
-						log.StringAttr("cpu_id", cpuElegida.ID),
-						log.IntAttr("pid", proceso.PCB.PID),
-					)
-
-					newPC, _ := cpuElegida.DispatchProcess()
-					proceso.PCB.PC = newPC
-
-					// Liberar CPU usando semáforo
-					p.LiberarCPU(cpuElegida)
-
-					p.Log.Debug("Proceso completado en CPU",
-						log.StringAttr("cpu_id", cpuElegida.ID),
-						//log.IntAttr("pid", proceso.PCB.PID),
-						log.IntAttr("pc_final", newPC),
-					)
-
-				}(cpuLibre, procesoElegido)
-			} else {
-				// No hay CPUs libres, salir del bucle interno
-				p.Log.Debug("No hay CPUs libres, esperando...")
-				break
-			}
-		}
-	}
+	return ready[0]
 }
 
-// PlanificarCortoPlazoSjfDesalojo elige al proceso que posea la ráfaga de CPU más corta. Al ingresar un proceso en
-// la cola de Ready y no haber CPUs libres, se debe evaluar si dicho proceso tiene una rafaga más corta que los que
-// se encuentran en ejecución. En caso de ser así, se debe informar al CPU que posea al Proceso con el tiempo restante
-// más alto que debe desalojar al mismo para que pueda ser planificado el nuevo.
-func (p *Service) PlanificarCortoPlazoSjfDesalojo() {
-	for {
-		// Esperar por notificación de nuevo proceso o procesar si ya hay procesos
-		select {
-		case <-p.canalNuevoProcesoReady:
-			p.Log.Debug("Notificación de nuevo proceso en Ready recibida (SJF)")
-		default:
-			// No hay notificación, pero verificar si hay procesos para procesar
-			if len(p.Planificador.ReadyQueue) == 0 {
-				// No hay procesos, esperar por notificación
-				p.Log.Debug("No hay procesos en ReadyQueue, esperando notificación... (SJF)")
-				<-p.canalNuevoProcesoReady
-			}
-		}
+func (s *schedulerSJF) AlIngresarReady(*internal.Proceso) {}
 
-		// Drenar el canal para evitar notificaciones acumuladas innecesarias
-	loopDrain:
-		for {
-			select {
-			case <-p.canalNuevoProcesoReady:
-				// Drenar notificación extra
-			default:
-				break loopDrain
-			}
-		}
-
-		p.mutexSRT.Lock()
-		for {
-			p.mutexReadyQueue.Lock()
-			if len(p.Planificador.ReadyQueue) == 0 {
-				p.mutexReadyQueue.Unlock()
-				break
-			}
-
-			p.ordenarColaReadySjf() // Ordena la cola de ReadyQueue por ráfaga estimada
+func (s *schedulerSJF) AlBloquearse(*internal.Proceso) {}
 
-			procesoNuevo := p.Planificador.ReadyQueue[0]
-			p.mutexReadyQueue.Unlock()
+func (s *schedulerSJF) AlSerDesalojado(*internal.Proceso, bool) {}
 
-			// Evaluar desalojo
-			if !p.evaluarDesalojo(procesoNuevo) && p.CantidadDeCpusDisponibles() > 0 {
-				// Si no se realizó desalojo, asignar el proceso a una CPU libre
-				if cpuLibre := p.BuscarCPUDisponible(); cpuLibre != nil {
-					p.asignarProcesoACPU(procesoNuevo, cpuLibre)
-				} else {
-					p.Log.Debug("No hay CPUs libres para asignar el nuevo proceso (SJF)")
-				}
-			}
-		}
-		p.mutexSRT.Unlock()
-	}
-}
-
-// PlanificarCortoPlazoSjfSinDesalojo planifica los procesos de corto plazo utilizando el algoritmo SJF sin desalojo.
-func (p *Service) PlanificarCortoPlazoSjfSinDesalojo() {
-	for {
-		// Esperar por notificación de nuevo proceso o procesar si ya hay procesos
-		select {
-		case <-p.canalNuevoProcesoReady:
-			p.Log.Debug("Notificación de nuevo proceso en Ready recibida (SJF)")
-		default:
-			// No hay notificación, pero verificar si hay procesos para procesar
-			if len(p.Planificador.ReadyQueue) == 0 {
-				// No hay procesos, esperar por notificación
-				p.Log.Debug("No hay procesos en ReadyQueue, esperando notificación... (SJF)")
-				<-p.canalNuevoProcesoReady
-			}
-		}
-
-		// Procesar todos los procesos en ReadyQueue
-		for {
-			p.mutexReadyQueue.Lock()
-			if len(p.Planificador.ReadyQueue) == 0 {
-				p.mutexReadyQueue.Unlock()
-				break // Salir del bucle si no hay más procesos en ReadyQueue
-			}
-
-			p.ordenarColaReadySjf()
-			// Asignar el proceso con ráfaga más corta (el primero de ReadyQueue)
-			procesoMasCorto := p.Planificador.ReadyQueue[0]
-			p.mutexReadyQueue.Unlock()
-
-			cpuLibre := p.BuscarCPUDisponible()
-			if cpuLibre != nil {
-				p.asignarProcesoACPU(procesoMasCorto, cpuLibre)
-			}
-		}
-	}
-}
-
-// ordenarColaReadySfj ordena la cola de ReadyQueue por ráfaga estimada ascendente
-func (p *Service) ordenarColaReadySjf() {
-	sort.Slice(p.Planificador.ReadyQueue, func(i, j int) bool {
-		return p.calcularSiguienteEstimacion(p.Planificador.ReadyQueue[i]) < p.calcularSiguienteEstimacion(p.Planificador.ReadyQueue[j])
-	})
+func (s *schedulerSJF) Quantum(*internal.Proceso) int {
+	return 0
 }
 
 // calcularSiguienteEstimacion calcula la ráfaga estimada usando la fórmula: Est(n+1) = α * R(n) + (1-α) * Est(n)
@@ -248,21 +81,52 @@ func (p *Service) evaluarDesalojo(procesoNuevo *internal.Proceso) bool {
 		return desalojoRealizado
 	}
 
+	p.mutexExecQueue.Lock()
+	procesoADesalojar := p.Scheduler.DebeDesalojar(procesoNuevo, p.Planificador.ExecQueue)
+	p.mutexExecQueue.Unlock()
+
+	if procesoADesalojar != nil {
+		p.Log.Debug("🎯 DESALOJO - Proceso seleccionado para desalojo",
+			log.StringAttr("algoritmo", p.ShortTermAlgorithm),
+			log.IntAttr("pid_desalojado", procesoADesalojar.PCB.PID),
+			log.IntAttr("pid_nuevo", procesoNuevo.PCB.PID),
+		)
+
+		if cpuLiberada := p.desalojarProceso(procesoADesalojar); cpuLiberada != nil {
+			if !p.asignarProcesoACPU(procesoNuevo, cpuLiberada) {
+				p.LiberarCPU(cpuLiberada)
+			}
+			desalojoRealizado = true
+		}
+	} else {
+		p.Log.Debug("❌ No se encontró proceso para desalojar")
+	}
+
+	return desalojoRealizado
+}
+
+// DebeDesalojar devuelve el proceso en ejecución con mayor tiempo restante, siempre que éste sea mayor a la ráfaga
+// estimada del proceso nuevo. Sin desalojo (SJF) nunca devuelve un candidato.
+func (s *schedulerSJF) DebeDesalojar(procesoNuevo *internal.Proceso, exec []*internal.Proceso) *internal.Proceso {
+	if !s.desalojo {
+		return nil
+	}
+
+	p := s.service
 	rafagaNueva := p.calcularSiguienteEstimacion(procesoNuevo)
 	p.Log.Debug("🚀 Evaluando desalojo SRT",
 		log.IntAttr("pid_nuevo", procesoNuevo.PCB.PID),
 		log.AnyAttr("rafaga_nueva", rafagaNueva),
-		log.IntAttr("procesos_en_exec", len(p.Planificador.ExecQueue)),
+		log.IntAttr("procesos_en_exec", len(exec)),
 	)
 	var (
 		procesoADesalojar *internal.Proceso
 		tiempoMax         float64 = -1 // Inicializar con un valor muy bajo
 	)
 
-	p.mutexExecQueue.Lock()
-	for _, procesoEjecutando := range p.Planificador.ExecQueue {
+	for _, procesoEjecutando := range exec {
 		// Calcular tiempo restante del proceso en ejecución
-		tiempoEjecutado := float64(time.Since(procesoEjecutando.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio).Milliseconds())
+		tiempoEjecutado := float64(clock.Since(procesoEjecutando.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio).Milliseconds())
 		//tiempoAcumulado := float64(procesoEjecutando.PCB.MetricasTiempo[internal.EstadoExec].TiempoAcumulado.Milliseconds())
 		rafagaEstimada := p.calcularSiguienteEstimacion(procesoEjecutando)
 		//tiempoRestante := rafagaEstimada - (tiempoAcumulado + tiempoEjecutado)
@@ -297,24 +161,7 @@ func (p *Service) evaluarDesalojo(procesoNuevo *internal.Proceso) bool {
 		}
 	}
 
-	p.mutexExecQueue.Unlock()
-
-	if procesoADesalojar != nil {
-		p.Log.Debug("🎯 DESALOJO SRT - Proceso seleccionado para desalojo",
-			log.IntAttr("pid_desalojado", procesoADesalojar.PCB.PID),
-			log.IntAttr("pid_nuevo", procesoNuevo.PCB.PID),
-			log.AnyAttr("rafaga_nueva", rafagaNueva),
-		)
-
-		if cpuLiberada := p.desalojarProceso(procesoADesalojar); cpuLiberada != nil {
-			p.asignarProcesoACPU(procesoNuevo, cpuLiberada)
-			desalojoRealizado = true
-		}
-	} else {
-		p.Log.Debug("❌ No se encontró proceso para desalojar")
-	}
-
-	return desalojoRealizado
+	return procesoADesalojar
 }
 
 // actualizarRafagaAnterior actualiza la ráfaga anterior y estimación anterior del proceso
@@ -322,12 +169,8 @@ func (p *Service) evaluarDesalojo(procesoNuevo *internal.Proceso) bool {
 func (p *Service) actualizarRafagaAnterior(proceso *internal.Proceso) {
 	var tiempoEjecutado time.Duration
 	if proceso != nil && proceso.PCB != nil {
-		tiempoEjecutado = time.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
-
-		// Actualizar tiempo acumulado de ejecución
-		if proceso.PCB.MetricasTiempo[internal.EstadoExec] != nil {
-			proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoAcumulado += tiempoEjecutado
-		}
+		// El tiempo acumulado en EXEC lo suma quien saca al proceso de ExecQueue; acá solo se mide la ráfaga
+		tiempoEjecutado = clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
 
 		// Calcular NUEVA estimación usando la ráfaga anterior actual
 		nuevaEstimacion := p.calcularSiguienteEstimacion(proceso) // 1000
@@ -358,7 +201,7 @@ func (p *Service) desalojarProceso(proceso *internal.Proceso) *cpu.Cpu {
 	cpuFound := p.buscarCPUPorPID(proceso.PCB.PID)
 	if cpuFound != nil {
 		cpuFound.EnviarInterrupcion("Desalojo", false)
-		// La CPU será liberada por DispatchProcess cuando termine, por lo que debemos esperar al semaforo
+		// La CPU será liberada cuando devuelva el contexto, por lo que debemos esperar al semaforo
 		<-p.CPUSemaphore
 
 		// Verificar que el proceso AÚN esté en EXEC después de obtener el semáforo
@@ -377,27 +220,28 @@ func (p *Service) desalojarProceso(proceso *internal.Proceso) *cpu.Cpu {
 		p.Planificador.ExecQueue, found = p.removerDeCola(proceso.PCB.PID, p.Planificador.ExecQueue)
 		if found {
 			proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoAcumulado +=
-				time.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
+				clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
+		}
+
+		switch p.ShortTermAlgorithm {
+		case "SJF", "SRT":
+			// Log obligatorio: Desalojo de SJF/SRT
+			//"## (<PID>) - Desalojado por algoritmo SJF/SRT"
+			p.Log.Info(fmt.Sprintf("## (%d) - Desalojado por algoritmo SJF/SRT", proceso.PCB.PID))
+		default:
+			p.Log.Info(fmt.Sprintf("## (%d) - Desalojado por algoritmo %s", proceso.PCB.PID, p.ShortTermAlgorithm))
 		}
 
-		// Log obligatorio: Desalojo de SJF/SRT
-		//"## (<PID>) - Desalojado por algoritmo SJF/SRT"
-		p.Log.Info(fmt.Sprintf("## (%d) - Desalojado por algoritmo SJF/SRT", proceso.PCB.PID))
+		p.Scheduler.AlSerDesalojado(proceso, false)
+		metricaDesalojos.Inc(MotivoDesalojoAlgoritmo)
 
 		// Devolver a ReadyQueue con protección de mutex
 		p.mutexReadyQueue.Lock()
-		p.Planificador.ReadyQueue = append(p.Planificador.ReadyQueue, proceso)
-
-		// Actualizar métricas de Ready
-		if proceso.PCB.MetricasTiempo[internal.EstadoReady] == nil {
-			proceso.PCB.MetricasTiempo[internal.EstadoReady] = &internal.EstadoTiempo{}
-		}
-		proceso.PCB.MetricasTiempo[internal.EstadoReady].TiempoInicio = time.Now()
-		proceso.PCB.MetricasEstado[internal.EstadoReady]++
+		p.agregarAReady(proceso)
 
 		//Log obligatorio: Cambio de estado
 		// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-		p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado EXEC al estado READY", proceso.PCB.PID))
+		p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoExec, internal.EstadoReady, "")
 
 		p.mutexReadyQueue.Unlock()
 
@@ -473,19 +317,23 @@ func (p *Service) asignarProcesoACPU(proceso *internal.Proceso, cpuAsignada *cpu
 		p.mutexReadyQueue.Unlock()
 		return asignado
 	}
+	p.sacarDeColasCPU(proceso.PCB.PID)
 
 	timeReady := proceso.PCB.MetricasTiempo[internal.EstadoReady]
 	if timeReady != nil {
-		timeReady.TiempoAcumulado += time.Since(timeReady.TiempoInicio)
+		timeReady.TiempoAcumulado += clock.Since(timeReady.TiempoInicio)
 	}
 	p.mutexReadyQueue.Unlock()
 
 	p.mutexCPUsConectadas.Lock()
 	cpuAsignada.Proceso.PID = proceso.PCB.PID
 	cpuAsignada.Proceso.PC = proceso.PCB.PC
+	cpuAsignada.Proceso.Tamanio = proceso.PCB.TamanioBytes
 	cpuAsignada.Estado = false
 	p.mutexCPUsConectadas.Unlock()
 
+	p.registrarAfinidad(proceso, cpuAsignada)
+
 	// Agregar a ExecQueue
 	p.mutexExecQueue.Lock()
 	p.Planificador.ExecQueue = append(p.Planificador.ExecQueue, proceso)
@@ -493,7 +341,7 @@ func (p *Service) asignarProcesoACPU(proceso *internal.Proceso, cpuAsignada *cpu
 	if proceso.PCB.MetricasTiempo[internal.EstadoExec] == nil {
 		proceso.PCB.MetricasTiempo[internal.EstadoExec] = &internal.EstadoTiempo{}
 	}
-	proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio = time.Now()
+	proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio = clock.Now()
 	proceso.PCB.MetricasEstado[internal.EstadoExec]++
 
 	p.Log.Debug("Proceso asignado a CPU con SRT/SJF",
@@ -503,64 +351,16 @@ func (p *Service) asignarProcesoACPU(proceso *internal.Proceso, cpuAsignada *cpu
 
 	//Log obligatorio: Cambio de estado
 	// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-	p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado READY al estado EXEC", proceso.PCB.PID))
+	p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoReady, internal.EstadoExec, cpuAsignada.ID)
 
 	asignado = true
 
-	go func(cpuElegida *cpu.Cpu, procesoExec *internal.Proceso) {
-		// Enviar proceso a la CPU
-		newPC, motivo := cpuElegida.DispatchProcess()
-		if procesoExec != nil && procesoExec.PCB != nil {
-			procesoExec.PCB.PC = newPC
-		}
-
-		// Si hubo error al ejecutar el ciclo u otro problema, quitar de ExecQueue
-		if motivo != "Proceso ejecutado exitosamente" {
-			p.Log.Debug("Proceso desalojado",
-				log.IntAttr("PID", procesoExec.PCB.PID),
-				log.StringAttr("motivo", motivo),
-			)
-		}
-
-		// Actualizar ráfaga anterior y estimación
-		//p.actualizarRafagaAnterior(procesoExec)
-
-		// Remover de ExecQueue solo si el proceso está allí
-		/*p.mutexExecQueue.Lock()
-		found := false
-		p.Planificador.ExecQueue, found = p.removerDeCola(procesoExec.PCB.PID, p.Planificador.ExecQueue)
-		if !found {
-			p.Log.Error("🚨 Proceso no encontrado en ExecQueue durante asignarProcesoACPU",
-				log.IntAttr("pid", procesoExec.PCB.PID),
-			)
-		}
-		p.mutexExecQueue.Unlock()
-
-		// Voler a agregar a ReadyQueue
-		p.mutexReadyQueue.Lock()
-		p.Planificador.ReadyQueue = append(p.Planificador.ReadyQueue, procesoExec)
-		if procesoExec.PCB.MetricasTiempo[internal.EstadoReady] == nil {
-			procesoExec.PCB.MetricasTiempo[internal.EstadoReady] = &internal.EstadoTiempo{}
-		}
-		procesoExec.PCB.MetricasTiempo[internal.EstadoReady].TiempoInicio = time.Now()
-		procesoExec.PCB.MetricasEstado[internal.EstadoReady]++
-		p.mutexReadyQueue.Unlock()*/
-
-		// Log obligatorio: Cambio de estado
-		// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-		//p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado EXEC al estado READY", procesoExec.PCB.PID))
-
-		//p.canalNuevoProcesoReady <- struct{}{} // Notificar que hay un nuevo proceso en ReadyQueue
-
-		//return
-		//}
-
-		// Actualizar ráfaga anterior y estimación
-		p.actualizarRafagaAnterior(procesoExec)
+	// Programar el fin de quantum (solo si el Scheduler usa quantum)
+	detenerQuantum := p.iniciarQuantum(proceso, cpuAsignada)
 
-		// Liberar CPU usando semáforo
-		p.LiberarCPU(cpuElegida)
-	}(cpuAsignada, proceso)
+	// La CPU devuelve el contexto por /cpu/contexto al terminar la ráfaga (ver RecibirContexto)
+	p.registrarDespacho(proceso, cpuAsignada, detenerQuantum)
+	go p.despachar(cpuAsignada, proceso)
 
 	p.mutexExecQueue.Unlock()
 
@@ -9,6 +9,9 @@ func (p *Service) AddCpuConectada(cpuId *CpuIdentificacion) {
 	newCPU := cpu.NewCpu(cpuId.IP, cpuId.Puerto, cpuId.ID, p.Log)
 	// Agregar la CPU a la lista de CPU conectadas
 	p.CPUsConectadas = append(p.CPUsConectadas, newCPU)
+	if p.ColasPorCPU {
+		p.colaDeCPU(cpuId.ID)
+	}
 
 	// Agregar un token al semáforo para indicar que hay una CPU más disponible
 	p.CPUSemaphore <- struct{}{}
@@ -0,0 +1,322 @@
+package planificadores
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+const (
+	DeteccionDeadlockNinguna    = "NONE"     // No se buscan deadlocks
+	DeteccionDeadlockAlBloquear = "ON_BLOCK" // Se buscan cada vez que un proceso se bloquea por un recurso (por defecto)
+	DeteccionDeadlockPeriodica  = "PERIODIC" // Se buscan cada deadlock_interval milisegundos
+
+	RecuperacionDeadlockNinguna        = "NONE"                 // Solo se informa el deadlock (por defecto)
+	RecuperacionDeadlockMasJoven       = "KILL_YOUNGEST"        // Se finaliza al proceso del ciclo creado último
+	RecuperacionDeadlockMenorPrioridad = "KILL_LOWEST_PRIORITY" // Se finaliza al proceso del ciclo de menor prioridad
+
+	MotivoFinalizacionDeadlock = "DEADLOCK" // Motivo de los procesos finalizados para resolver un deadlock
+
+	intervaloDeadlockPorDefecto = 1000 // Milisegundos entre búsquedas con la detección periódica
+)
+
+// DeadlockConfig configura la detección de deadlocks sobre los recursos del kernel
+type DeadlockConfig struct {
+	Deteccion    string `json:"deadlock_detection"` // NONE, ON_BLOCK o PERIODIC
+	Intervalo    int    `json:"deadlock_interval"`  // Milisegundos entre búsquedas (PERIODIC)
+	Recuperacion string `json:"deadlock_recovery"`  // NONE, KILL_YOUNGEST o KILL_LOWEST_PRIORITY
+}
+
+// victimasDeadlock elige, según la recuperación configurada, qué proceso del ciclo se finaliza
+var victimasDeadlock = map[string]func(ciclo []*internal.Proceso) *internal.Proceso{
+	RecuperacionDeadlockMasJoven: func(ciclo []*internal.Proceso) *internal.Proceso {
+		return elegirDelCiclo(ciclo, func(a, b *internal.Proceso) bool { return a.PCB.PID > b.PCB.PID })
+	},
+	RecuperacionDeadlockMenorPrioridad: func(ciclo []*internal.Proceso) *internal.Proceso {
+		return elegirDelCiclo(ciclo, func(a, b *internal.Proceso) bool {
+			if a.PCB.Prioridad != b.PCB.Prioridad {
+				return a.PCB.Prioridad > b.PCB.Prioridad
+			}
+			return a.PCB.PID > b.PCB.PID
+		})
+	},
+}
+
+// cicloDeadlock es un ciclo del grafo de espera: Procesos[i] espera Recursos[i], que tiene asignado Procesos[i+1]
+// (y el último espera un recurso del primero)
+type cicloDeadlock struct {
+	Procesos []*internal.Proceso
+	Recursos []string
+}
+
+// ConfigurarDeadlocks elige cuándo se buscan deadlocks entre los recursos del kernel y cómo se resuelven. Con la
+// detección periódica, inicia la goroutine que los busca.
+func (p *Service) ConfigurarDeadlocks(config *DeadlockConfig) {
+	config.Deteccion = strings.ToUpper(config.Deteccion)
+	switch config.Deteccion {
+	case DeteccionDeadlockNinguna, DeteccionDeadlockAlBloquear, DeteccionDeadlockPeriodica:
+	case "":
+		config.Deteccion = DeteccionDeadlockAlBloquear
+	default:
+		p.Log.Warn("Detección de deadlocks no reconocida, se usará ON_BLOCK",
+			log.StringAttr("deteccion", config.Deteccion),
+		)
+		config.Deteccion = DeteccionDeadlockAlBloquear
+	}
+
+	config.Recuperacion = strings.ToUpper(config.Recuperacion)
+	if _, ok := victimasDeadlock[config.Recuperacion]; !ok {
+		if config.Recuperacion != "" && config.Recuperacion != RecuperacionDeadlockNinguna {
+			p.Log.Warn("Recuperación de deadlocks no reconocida, solo se informarán",
+				log.StringAttr("recuperacion", config.Recuperacion),
+			)
+		}
+		config.Recuperacion = RecuperacionDeadlockNinguna
+	}
+
+	if config.Intervalo <= 0 {
+		config.Intervalo = intervaloDeadlockPorDefecto
+	}
+
+	p.DeadlockConfig = config
+
+	if config.Deteccion == DeteccionDeadlockPeriodica {
+		go p.detectarDeadlocksPeriodicamente()
+	}
+}
+
+func (p *Service) detectarDeadlocksPeriodicamente() {
+	for {
+		<-clock.After(time.Duration(p.DeadlockConfig.Intervalo) * time.Millisecond)
+		p.DetectarDeadlocks()
+	}
+}
+
+// alBloquearsePorRecurso se llama cada vez que un proceso queda esperando un recurso
+func (p *Service) alBloquearsePorRecurso() {
+	if p.DeadlockConfig != nil && p.DeadlockConfig.Deteccion == DeteccionDeadlockAlBloquear {
+		p.DetectarDeadlocks()
+	}
+}
+
+// DetectarDeadlocks arma el grafo de espera de los recursos del kernel, informa cada ciclo y, según la recuperación
+// configurada, finaliza a un proceso de cada uno. Devuelve los ciclos encontrados.
+func (p *Service) DetectarDeadlocks() []cicloDeadlock {
+	recuperacion := RecuperacionDeadlockNinguna
+	if p.DeadlockConfig != nil {
+		recuperacion = p.DeadlockConfig.Recuperacion
+	}
+
+	// Los procesos de los ciclos siguen bloqueados mientras se tenga mutexRecursos: se informan y se eligen las
+	// víctimas antes de liberarlo, y después solo se usan sus PIDs
+	p.mutexRecursos.Lock()
+	ciclos := p.buscarCiclos()
+	finalizados := make(map[int]bool)
+	var victimas []int
+	for _, ciclo := range ciclos {
+		p.informarDeadlock(ciclo)
+		metricaDeadlocks.Inc()
+
+		elegir, ok := victimasDeadlock[recuperacion]
+		if !ok || cicloResuelto(ciclo, finalizados) {
+			continue
+		}
+
+		victima := elegir(ciclo.Procesos).PCB.PID
+		finalizados[victima] = true
+		victimas = append(victimas, victima)
+	}
+	p.mutexRecursos.Unlock()
+
+	for _, pid := range victimas {
+		p.Log.Info(fmt.Sprintf("## (%d) - Finalizado para resolver un deadlock - Recuperación: %s",
+			pid, recuperacion))
+
+		go func(pid int) {
+			if err := p.finalizarDesdeCualquierEstado(pid, MotivoFinalizacionDeadlock); err != nil {
+				p.Log.Error("Error al finalizar proceso para resolver un deadlock",
+					log.ErrAttr(err),
+					log.IntAttr("pid", pid),
+				)
+			}
+		}(pid)
+	}
+
+	return ciclos
+}
+
+// buscarCiclos recorre el grafo de espera: cada proceso bloqueado en un recurso espera a los procesos que tienen
+// asignada una instancia de ese recurso. Como un recurso puede tener varias instancias, un ciclo solo es deadlock si
+// ninguno de sus procesos puede obtener la instancia de un dueño que no está trabado. Devuelve los ciclos que cierra
+// el DFS, que pueden ser varios en un mismo componente si comparten procesos: quien los recupere debe saltear los
+// que ya quedaron resueltos por una víctima anterior.
+// IMPORTANTE: Se llama con mutexRecursos bloqueado
+func (p *Service) buscarCiclos() []cicloDeadlock {
+	type arista struct {
+		destino int
+		recurso string
+	}
+
+	procesos := make(map[int]*internal.Proceso)
+	aristas := make(map[int][]arista)
+
+	nombres := make([]string, 0, len(p.recursos))
+	for nombre := range p.recursos {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+
+	for _, nombre := range nombres {
+		for _, esperando := range p.recursos[nombre].esperando {
+			if esperando == nil || esperando.PCB == nil {
+				continue
+			}
+			procesos[esperando.PCB.PID] = esperando
+
+			for _, duenio := range p.duenios(nombre) {
+				aristas[esperando.PCB.PID] = append(aristas[esperando.PCB.PID], arista{destino: duenio, recurso: nombre})
+			}
+		}
+	}
+
+	// Un proceso que espera sale del deadlock si algún dueño de su recurso no está trabado: no espera ningún recurso o
+	// también sale. Se repite hasta que no cambie nada.
+	trabados := make(map[int]bool, len(procesos))
+	for pid := range procesos {
+		trabados[pid] = true
+	}
+	for cambio := true; cambio; {
+		cambio = false
+		for pid := range trabados {
+			for _, a := range aristas[pid] {
+				if !trabados[a.destino] {
+					delete(trabados, pid)
+					cambio = true
+					break
+				}
+			}
+		}
+	}
+
+	pids := make([]int, 0, len(trabados))
+	for pid := range trabados {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+
+	// DFS: un proceso en la pila que vuelve a alcanzarse cierra un ciclo
+	const (
+		sinVisitar = iota
+		enPila
+		terminado
+	)
+	estado := make(map[int]int)
+	var pila []int
+	var recursosPila []string
+	var ciclos []cicloDeadlock
+
+	var visitar func(pid int)
+	visitar = func(pid int) {
+		estado[pid] = enPila
+		pila = append(pila, pid)
+
+		for _, a := range aristas[pid] {
+			if !trabados[a.destino] {
+				continue
+			}
+			recursosPila = append(recursosPila, a.recurso)
+			switch estado[a.destino] {
+			case sinVisitar:
+				visitar(a.destino)
+			case enPila:
+				inicio := indiceDe(pila, a.destino)
+				ciclo := cicloDeadlock{Recursos: append([]string(nil), recursosPila[inicio:]...)}
+				for _, enCiclo := range pila[inicio:] {
+					ciclo.Procesos = append(ciclo.Procesos, procesos[enCiclo])
+				}
+				ciclos = append(ciclos, ciclo)
+			}
+			recursosPila = recursosPila[:len(recursosPila)-1]
+		}
+
+		pila = pila[:len(pila)-1]
+		estado[pid] = terminado
+	}
+
+	for _, pid := range pids {
+		if estado[pid] == sinVisitar {
+			visitar(pid)
+		}
+	}
+
+	return ciclos
+}
+
+// duenios devuelve, ordenados, los PIDs que tienen asignada alguna instancia del recurso
+// IMPORTANTE: Se llama con mutexRecursos bloqueado
+func (p *Service) duenios(recurso string) []int {
+	var pids []int
+	for pid, asignadas := range p.asignaciones {
+		if asignadas[recurso] > 0 {
+			pids = append(pids, pid)
+		}
+	}
+	sort.Ints(pids)
+
+	return pids
+}
+
+// informarDeadlock escribe el ciclo en una línea con formato fijo, para poder procesar los logs:
+// "## Deadlock detectado - PIDs: 1,2 - Recursos: R1,R2 - Ciclo: (1) -R1-> (2) -R2-> (1)"
+// donde cada proceso espera el recurso que tiene asignado el siguiente.
+func (p *Service) informarDeadlock(ciclo cicloDeadlock) {
+	pids := make([]string, len(ciclo.Procesos))
+	var recorrido strings.Builder
+	for i, proceso := range ciclo.Procesos {
+		pids[i] = strconv.Itoa(proceso.PCB.PID)
+		recorrido.WriteString(fmt.Sprintf("(%d) -%s-> ", proceso.PCB.PID, ciclo.Recursos[i]))
+	}
+	recorrido.WriteString(fmt.Sprintf("(%d)", ciclo.Procesos[0].PCB.PID))
+
+	p.Log.Warn(fmt.Sprintf("## Deadlock detectado - PIDs: %s - Recursos: %s - Ciclo: %s",
+		strings.Join(pids, ","), strings.Join(ciclo.Recursos, ","), recorrido.String()),
+		log.StringAttr("pids", strings.Join(pids, ",")),
+		log.StringAttr("recursos", strings.Join(ciclo.Recursos, ",")),
+	)
+}
+
+// cicloResuelto informa si ya se finalizó algún proceso del ciclo
+func cicloResuelto(ciclo cicloDeadlock, finalizados map[int]bool) bool {
+	for _, proceso := range ciclo.Procesos {
+		if finalizados[proceso.PCB.PID] {
+			return true
+		}
+	}
+	return false
+}
+
+// elegirDelCiclo devuelve el proceso del ciclo que es mejor víctima que todos los demás según el criterio
+func elegirDelCiclo(ciclo []*internal.Proceso, mejor func(a, b *internal.Proceso) bool) *internal.Proceso {
+	elegido := ciclo[0]
+	for _, proceso := range ciclo[1:] {
+		if mejor(proceso, elegido) {
+			elegido = proceso
+		}
+	}
+	return elegido
+}
+
+func indiceDe(pids []int, pid int) int {
+	for i, p := range pids {
+		if p == pid {
+			return i
+		}
+	}
+	return -1
+}
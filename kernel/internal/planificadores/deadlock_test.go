@@ -0,0 +1,106 @@
+package planificadores
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+)
+
+func TestDeadlock_BuscarCiclos(t *testing.T) {
+	proceso := func(pid int) *internal.Proceso {
+		return &internal.Proceso{PCB: &internal.PCB{PID: pid}}
+	}
+
+	tests := []struct {
+		nombre       string
+		esperando    map[string][]int       // Procesos bloqueados en cada recurso
+		asignaciones map[int]map[string]int // Instancias asignadas a cada proceso
+		ciclos       [][]int                // PIDs de cada ciclo encontrado
+		recursos     [][]string             // Recursos de cada ciclo encontrado
+	}{
+		{
+			nombre:       "dos procesos esperan el recurso del otro",
+			esperando:    map[string][]int{"A": {2}, "B": {1}},
+			asignaciones: map[int]map[string]int{1: {"A": 1}, 2: {"B": 1}},
+			ciclos:       [][]int{{1, 2}},
+			recursos:     [][]string{{"B", "A"}},
+		},
+		{
+			nombre:       "un dueño del recurso con varias instancias no está trabado",
+			esperando:    map[string][]int{"R": {2}, "S": {1}},
+			asignaciones: map[int]map[string]int{1: {"R": 1}, 2: {"S": 1}, 3: {"R": 1}},
+		},
+		{
+			nombre:       "dos ciclos independientes",
+			esperando:    map[string][]int{"A": {2}, "B": {1}, "C": {4}, "D": {3}},
+			asignaciones: map[int]map[string]int{1: {"A": 1}, 2: {"B": 1}, 3: {"C": 1}, 4: {"D": 1}},
+			ciclos:       [][]int{{1, 2}, {3, 4}},
+			recursos:     [][]string{{"B", "A"}, {"D", "C"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			p := &Service{
+				mutexRecursos: &sync.Mutex{},
+				recursos:      make(map[string]*recurso),
+				asignaciones:  tt.asignaciones,
+			}
+			for nombre, pids := range tt.esperando {
+				r := &recurso{nombre: nombre}
+				for _, pid := range pids {
+					r.esperando = append(r.esperando, proceso(pid))
+				}
+				p.recursos[nombre] = r
+			}
+
+			p.mutexRecursos.Lock()
+			encontrados := p.buscarCiclos()
+			p.mutexRecursos.Unlock()
+
+			var ciclos [][]int
+			var recursos [][]string
+			for _, ciclo := range encontrados {
+				var pids []int
+				for _, enCiclo := range ciclo.Procesos {
+					pids = append(pids, enCiclo.PCB.PID)
+				}
+				ciclos = append(ciclos, pids)
+				recursos = append(recursos, ciclo.Recursos)
+			}
+
+			if !reflect.DeepEqual(ciclos, tt.ciclos) {
+				t.Errorf("ciclos = %v, se esperaba %v", ciclos, tt.ciclos)
+			}
+			if !reflect.DeepEqual(recursos, tt.recursos) {
+				t.Errorf("recursos = %v, se esperaba %v", recursos, tt.recursos)
+			}
+		})
+	}
+}
+
+func TestDeadlock_Victimas(t *testing.T) {
+	ciclo := []*internal.Proceso{
+		{PCB: &internal.PCB{PID: 1, Prioridad: 2}},
+		{PCB: &internal.PCB{PID: 3, Prioridad: 0}},
+		{PCB: &internal.PCB{PID: 2, Prioridad: 2}},
+	}
+
+	tests := []struct {
+		recuperacion string
+		victima      int
+	}{
+		{RecuperacionDeadlockMasJoven, 3},
+		{RecuperacionDeadlockMenorPrioridad, 2}, // Empatan 1 y 2 con la menor prioridad, se elige el más joven
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.recuperacion, func(t *testing.T) {
+			if victima := victimasDeadlock[tt.recuperacion](ciclo); victima.PCB.PID != tt.victima {
+				t.Errorf("víctima = %d, se esperaba %d", victima.PCB.PID, tt.victima)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package planificadores
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/kernel/pkg/cpu"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/contexto"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// despacho es una ráfaga en curso: el proceso fue enviado a la CPU y todavía no devolvió el contexto
+type despacho struct {
+	proceso        *internal.Proceso
+	cpu            *cpu.Cpu
+	inicio         time.Time   // Instante del despacho según el reloj del kernel
+	detenerQuantum func() bool // Cancela el fin de quantum e indica si ya había vencido
+}
+
+// registrarDespacho guarda la ráfaga que se está por enviar a la CPU, para atender su contexto cuando vuelva
+func (p *Service) registrarDespacho(proceso *internal.Proceso, cpuAsignada *cpu.Cpu, detenerQuantum func() bool) {
+	p.mutexDespachos.Lock()
+	defer p.mutexDespachos.Unlock()
+
+	p.despachos[proceso.PCB.PID] = &despacho{
+		proceso:        proceso,
+		cpu:            cpuAsignada,
+		inicio:         clock.Now(),
+		detenerQuantum: detenerQuantum,
+	}
+}
+
+// tomarDespacho devuelve la ráfaga en curso del proceso y la da por terminada
+func (p *Service) tomarDespacho(pid int) (*despacho, bool) {
+	p.mutexDespachos.Lock()
+	defer p.mutexDespachos.Unlock()
+
+	d, ok := p.despachos[pid]
+	delete(p.despachos, pid)
+	return d, ok
+}
+
+// AvanzarRelojRafaga adelanta el reloj del kernel hasta el instante en que la ráfaga en curso lleva ejecutando lo que
+// informa la CPU. Con el reloj virtual, así vencen el quantum y los demás temporizadores mientras el proceso ejecuta;
+// con varias CPUs el reloj queda en la ráfaga más avanzada. Con el reloj real no tiene efecto.
+func (p *Service) AvanzarRelojRafaga(avance contexto.Avance) error {
+	p.mutexDespachos.Lock()
+	d, ok := p.despachos[avance.PID]
+	p.mutexDespachos.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: no hay una ráfaga en curso para el PID %d", ErrProcesoNoEncontrado, avance.PID)
+	}
+
+	clock.AvanzarHasta(d.inicio.Add(time.Duration(avance.Rafaga) * time.Millisecond))
+	return nil
+}
+
+// despachar envía el proceso a la CPU. Si la CPU no lo acepta, la ráfaga termina sin ejecutar: el proceso vuelve a
+// READY y la CPU se libera.
+func (p *Service) despachar(cpuElegida *cpu.Cpu, proceso *internal.Proceso) {
+	if err := cpuElegida.DispatchProcess(); err != nil {
+		p.Log.Error("Error al despachar el proceso a la CPU",
+			log.ErrAttr(err),
+			log.IntAttr("pid", proceso.PCB.PID),
+			log.StringAttr("cpu_id", cpuElegida.ID),
+		)
+
+		if d, ok := p.tomarDespacho(proceso.PCB.PID); ok {
+			d.detenerQuantum()
+			p.devolverAReadySinEjecutar(d.proceso)
+			p.LiberarCPU(d.cpu)
+		}
+	}
+}
+
+// devolverAReadySinEjecutar vuelve a poner en READY a un proceso que la CPU no llegó a ejecutar. No cuenta como
+// desalojo: el proceso no consumió quantum ni cambia su ráfaga anterior.
+func (p *Service) devolverAReadySinEjecutar(proceso *internal.Proceso) {
+	if p.sacarDeExec(proceso) {
+		p.reingresarAReady(proceso)
+	}
+}
+
+// devolverAReadyPorSyscallFallida vuelve a poner en READY a un proceso cuya syscall no llegó al kernel. Para el
+// Scheduler es un desalojo sin fin de quantum.
+func (p *Service) devolverAReadyPorSyscallFallida(proceso *internal.Proceso) {
+	if !p.sacarDeExec(proceso) {
+		return
+	}
+
+	p.Scheduler.AlSerDesalojado(proceso, false)
+	p.Log.Info(fmt.Sprintf("## (%d) - Desalojado porque su syscall no llegó al kernel", proceso.PCB.PID))
+
+	p.reingresarAReady(proceso)
+}
+
+// sacarDeExec saca al proceso de ExecQueue sumando el tiempo que estuvo en EXEC. Devuelve false si ya no estaba
+// porque una operación administrativa lo finalizó o suspendió mientras ejecutaba.
+func (p *Service) sacarDeExec(proceso *internal.Proceso) bool {
+	p.mutexExecQueue.Lock()
+	var found bool
+	p.Planificador.ExecQueue, found = p.removerDeCola(proceso.PCB.PID, p.Planificador.ExecQueue)
+	if found {
+		proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoAcumulado +=
+			clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
+	}
+	p.mutexExecQueue.Unlock()
+
+	if !found {
+		p.Log.Debug("El proceso ya no se encuentra en Exec, no se lo devuelve a Ready",
+			log.IntAttr("pid", proceso.PCB.PID),
+		)
+	}
+
+	return found
+}
+
+// reingresarAReady agrega a READY a un proceso que salió de EXEC y avisa al planificador de corto plazo
+func (p *Service) reingresarAReady(proceso *internal.Proceso) {
+	p.mutexReadyQueue.Lock()
+	p.agregarAReady(proceso)
+	p.mutexReadyQueue.Unlock()
+
+	//Log obligatorio: Cambio de estado
+	// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
+	p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoExec, internal.EstadoReady, "")
+
+	p.canalNuevoProcesoReady <- struct{}{}
+}
+
+// RecibirContexto atiende el contexto que devuelve la CPU al terminar una ráfaga. Según el motivo, el proceso vuelve
+// a READY por fin de quantum, se bloquea por la syscall (atenderSyscall) o finaliza. Después se libera la CPU.
+func (p *Service) RecibirContexto(ctx contexto.Contexto, atenderSyscall func()) error {
+	d, ok := p.tomarDespacho(ctx.PID)
+	if !ok {
+		return fmt.Errorf("%w: no hay una ráfaga en curso para el PID %d", ErrProcesoNoEncontrado, ctx.PID)
+	}
+
+	p.finalizarRafaga(d, ctx, atenderSyscall)
+	return nil
+}
+
+// finalizarRafaga cierra una ráfaga: es el único lugar donde se libera la CPU de un proceso despachado
+func (p *Service) finalizarRafaga(d *despacho, ctx contexto.Contexto, atenderSyscall func()) {
+	pid := d.proceso.PCB.PID
+	d.proceso.PCB.PC = ctx.PC
+
+	// Siempre se cancela el fin de quantum, aunque el proceso no vuelva por desalojo
+	vencioQuantum := d.detenerQuantum()
+
+	// Con el reloj virtual, el tiempo de la última instrucción pasa recién ahora, con el quantum ya cancelado
+	clock.AvanzarHasta(d.inicio.Add(time.Duration(ctx.Rafaga) * time.Millisecond))
+
+	p.Log.Debug("Contexto recibido",
+		log.IntAttr("PID", pid),
+		log.IntAttr("PC", ctx.PC),
+		log.StringAttr("motivo", string(ctx.Motivo)),
+	)
+
+	switch {
+	case ctx.Motivo == contexto.MotivoDesalojo:
+		// Si venció el quantum, vuelve a READY. Si no, lo desalojó el planificador (SRT) o una operación
+		// administrativa, que deciden su estado cuando se libera la CPU.
+		// La ráfaga se mide antes de volver a READY: después el proceso puede despacharse de nuevo
+		p.actualizarRafagaAnterior(d.proceso)
+		if vencioQuantum {
+			p.devolverAReadyPorQuantum(d.proceso)
+		}
+
+	case ctx.Motivo == contexto.MotivoSyscallFallida:
+		// La syscall no llegó al kernel: nadie más va a decidir su estado, así que vuelve a READY para reintentarla
+		p.actualizarRafagaAnterior(d.proceso)
+		p.devolverAReadyPorSyscallFallida(d.proceso)
+
+	case ctx.Motivo == contexto.MotivoSyscallBloqueante:
+		// Actualizar ráfaga anterior y estimación, antes de que la syscall pueda finalizar el proceso
+		p.actualizarRafagaAnterior(d.proceso)
+		if atenderSyscall != nil {
+			atenderSyscall()
+		} else {
+			p.Log.Error("La CPU devolvió una syscall bloqueante sin la syscall",
+				log.IntAttr("PID", pid),
+			)
+		}
+
+	case ctx.Motivo == contexto.MotivoExit:
+		//Log obligatorio: Syscall recibida
+		//"## (<PID>) - Solicitó syscall: <NOMBRE_SYSCALL>"
+		p.Log.Info(fmt.Sprintf("## (%d) - Solicitó syscall: EXIT", pid))
+		ContarSyscall("EXIT")
+
+		p.actualizarRafagaAnterior(d.proceso)
+		go p.FinalizarProcesoEnCualquierCola(pid)
+
+	default:
+		// Errores de ejecución (y motivos desconocidos): el proceso no puede continuar
+		detalle := &contexto.Error{Mensaje: "motivo desconocido"}
+		if ctx.Error != nil {
+			detalle = ctx.Error
+		}
+		p.Log.Error("El proceso terminó su ráfaga por un error, se finaliza",
+			log.IntAttr("PID", pid),
+			log.IntAttr("PC", ctx.PC),
+			log.StringAttr("motivo", string(ctx.Motivo)),
+			log.StringAttr("error", detalle.Mensaje),
+			log.StringAttr("instruccion", detalle.Instruccion),
+			log.AnyAttr("args", detalle.Args),
+			log.StringAttr("direccion", detalle.Direccion),
+		)
+		// El motivo queda en el PCB para las métricas finales
+		motivoFinalizacion := string(ctx.Motivo)
+		if detalle.Codigo != "" {
+			motivoFinalizacion = detalle.Codigo
+		}
+		d.proceso.PCB.MotivoFinalizacion = motivoFinalizacion
+		p.Log.Info(fmt.Sprintf("## (%d) - Finaliza por error: %s", pid, motivoFinalizacion))
+
+		p.actualizarRafagaAnterior(d.proceso)
+		go p.FinalizarProcesoEnCualquierCola(pid)
+	}
+
+	// Liberar CPU usando semáforo
+	p.LiberarCPU(d.cpu)
+}
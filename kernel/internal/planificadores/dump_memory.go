@@ -2,9 +2,9 @@ package planificadores
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -84,7 +84,10 @@ func (p *Service) moverProcesoExecABlocked(pid int) error {
 		return fmt.Errorf("proceso con PID %d no encontrado en EXEC", pid)
 	}
 	proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoAcumulado +=
-		time.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
+		clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
+
+	// Guardar el estado de planificación del proceso que se bloquea antes de consumir su quantum (VRR, MLFQ)
+	p.registrarBloqueo(proceso)
 
 	// Actualizar ráfaga anterior antes de mover a BLOCKED (IMPORTANTE para SRT - incluye métricas de tiempo EXEC)
 	//p.actualizarRafagaAnterior(proceso)
@@ -98,10 +101,10 @@ func (p *Service) moverProcesoExecABlocked(pid int) error {
 	if proceso.PCB.MetricasTiempo[internal.EstadoBloqueado] == nil {
 		proceso.PCB.MetricasTiempo[internal.EstadoBloqueado] = &internal.EstadoTiempo{}
 	}
-	proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoInicio = time.Now()
+	proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoInicio = clock.Now()
 	proceso.PCB.MetricasEstado[internal.EstadoBloqueado]++
 
-	p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado EXEC al estado BLOCKED", proceso.PCB.PID))
+	p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoExec, internal.EstadoBloqueado, RecursoDumpMemory)
 
 	// Notificar al planificador de mediano plazo
 	p.CanalNuevoProcBlocked <- proceso
@@ -130,6 +133,7 @@ func (p *Service) moverProcesoBlockedAReady(pid int) error {
 				log.IntAttr("pid", pid),
 			)
 		}
+		p.Temporizadores.Cancelar(pid) // Terminó el bloqueo, ya no se lo suspende por tiempo
 	}
 	p.mutexBlockQueue.Unlock()
 
@@ -140,21 +144,15 @@ func (p *Service) moverProcesoBlockedAReady(pid int) error {
 	// Actualizar métricas de tiempo para BLOCKED
 	if proceso.PCB.MetricasTiempo[internal.EstadoBloqueado] != nil {
 		proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoAcumulado +=
-			time.Since(proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoInicio)
+			clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoInicio)
 	}
 
 	// Agregar a READY
 	p.mutexReadyQueue.Lock()
-	p.Planificador.ReadyQueue = append(p.Planificador.ReadyQueue, proceso)
-	// Inicializar métricas de tiempo para READY
-	if proceso.PCB.MetricasTiempo[internal.EstadoReady] == nil {
-		proceso.PCB.MetricasTiempo[internal.EstadoReady] = &internal.EstadoTiempo{}
-	}
-	proceso.PCB.MetricasTiempo[internal.EstadoReady].TiempoInicio = time.Now()
-	proceso.PCB.MetricasEstado[internal.EstadoReady]++
+	p.agregarAReady(proceso)
 	p.mutexReadyQueue.Unlock()
 
-	p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado BLOCKED al estado READY", proceso.PCB.PID))
+	p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoBloqueado, internal.EstadoReady, "")
 
 	p.canalNuevoProcesoReady <- struct{}{} // Notificar al planificador de corto plazo
 
@@ -183,6 +181,7 @@ func (p *Service) moverProcesoBlockedAExit(pid int) error {
 				log.IntAttr("pid", pid),
 			)
 		}
+		p.Temporizadores.Cancelar(pid) // Terminó el bloqueo, ya no se lo suspende por tiempo
 	}
 	p.mutexBlockQueue.Unlock()
 
@@ -197,7 +196,7 @@ func (p *Service) moverProcesoBlockedAExit(pid int) error {
 		}
 	}
 	proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoAcumulado +=
-		time.Since(proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoInicio)
+		clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoInicio)
 
 	// Agregar a EXIT
 	p.Planificador.ExitQueue = append(p.Planificador.ExitQueue, proceso)
@@ -206,10 +205,10 @@ func (p *Service) moverProcesoBlockedAExit(pid int) error {
 	if proceso.PCB.MetricasTiempo[internal.EstadoExit] == nil {
 		proceso.PCB.MetricasTiempo[internal.EstadoExit] = &internal.EstadoTiempo{}
 	}
-	proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoInicio = time.Now()
+	proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoInicio = clock.Now()
 	proceso.PCB.MetricasEstado[internal.EstadoExit]++
 
-	p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado BLOCKED al estado EXIT", proceso.PCB.PID))
+	p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoBloqueado, internal.EstadoExit, "")
 
 	return nil
 }
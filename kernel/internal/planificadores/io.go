@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -23,6 +24,8 @@ func (p *Service) EnviarUsleep(puertoIO int, iPIO string, pid, timeSleep int) {
 		TiempoSleep: timeSleep,
 	}
 
+	p.registrarInicioIO(pid, timeSleep)
+
 	jsonData, err := json.Marshal(usleep)
 	if err != nil {
 		p.Log.Error("Error al serializar el usleep a JSON",
@@ -58,7 +61,20 @@ func (p *Service) EnviarUsleep(puertoIO int, iPIO string, pid, timeSleep int) {
 }
 
 // BloquearPorIO mueve un proceso de EXEC a BLOCKED por una operación de IO
-func (p *Service) BloquearPorIO(pid int) error {
+func (p *Service) BloquearPorIO(pid int, dispositivo string) error {
+	_, err := p.bloquear(pid, dispositivo, func(pcb *internal.PCB) {
+		if pcb.DispositivosIO == nil {
+			pcb.DispositivosIO = make(map[string]int)
+		}
+		pcb.DispositivosIO[dispositivo]++
+	})
+
+	return err
+}
+
+// bloquear mueve un proceso de EXEC a BLOCKED esperando el recurso dado (un dispositivo IO, WAIT, …). Si se indica,
+// registrar actualiza el PCB con el mutex de BlockQueue bloqueado.
+func (p *Service) bloquear(pid int, recurso string, registrar func(pcb *internal.PCB)) (*internal.Proceso, error) {
 	// Buscar el proceso en la cola de EXEC
 	var proceso *internal.Proceso
 
@@ -76,7 +92,7 @@ func (p *Service) BloquearPorIO(pid int) error {
 		p.Planificador.ExecQueue, removido = p.removerDeCola(pid, p.Planificador.ExecQueue)
 		if removido {
 			proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoAcumulado +=
-				time.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
+				clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
 		}
 	}
 
@@ -84,9 +100,12 @@ func (p *Service) BloquearPorIO(pid int) error {
 
 	if proceso == nil {
 		//p.mutexExecQueue.Unlock()
-		return fmt.Errorf("proceso con PID %d no encontrado en EXEC", pid)
+		return nil, fmt.Errorf("proceso con PID %d no encontrado en EXEC", pid)
 	}
 
+	// Guardar el estado de planificación del proceso que se bloquea antes de consumir su quantum (VRR, MLFQ)
+	p.registrarBloqueo(proceso)
+
 	// Actualizar ráfaga anterior antes de bloquear (IMPORTANTE para SRT - incluye métricas de tiempo EXEC)
 	//p.actualizarRafagaAnterior(proceso)
 
@@ -96,18 +115,43 @@ func (p *Service) BloquearPorIO(pid int) error {
 
 	//Log obligatorio: Cambio de estado
 	// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-	p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado EXEC al estado BLOCKED", pid))
+	p.registrarCambioEstado(pid, internal.EstadoExec, internal.EstadoBloqueado, recurso)
 
 	// Inicializar métricas de tiempo para BLOCKED
 	if proceso.PCB.MetricasTiempo[internal.EstadoBloqueado] == nil {
 		proceso.PCB.MetricasTiempo[internal.EstadoBloqueado] = &internal.EstadoTiempo{}
 	}
-	proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoInicio = time.Now()
+	proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoInicio = clock.Now()
 	proceso.PCB.MetricasEstado[internal.EstadoBloqueado]++
+	if registrar != nil {
+		registrar(proceso.PCB)
+	}
 	p.mutexBlockQueue.Unlock()
 
 	// Notificar al planificador de mediano plazo
 	p.CanalNuevoProcBlocked <- proceso
 
-	return nil
+	return proceso, nil
+}
+
+// registrarInicioIO guarda el instante en el que debería terminar la IO del proceso, para que el reloj del kernel
+// pueda adelantarse hasta ahí cuando la IO avise que terminó
+func (p *Service) registrarInicioIO(pid, timeSleep int) {
+	p.mutexFinesIO.Lock()
+	p.finesIO[pid] = clock.Now().Add(time.Duration(timeSleep) * time.Millisecond)
+	p.mutexFinesIO.Unlock()
+}
+
+// AvanzarRelojFinIO adelanta el reloj del kernel hasta el fin previsto de la IO del proceso. Con el reloj virtual,
+// así vencen en orden los temporizadores (suspensión, quantum) que debían vencer durante la IO. Con el reloj real no
+// tiene efecto.
+func (p *Service) AvanzarRelojFinIO(pid int) {
+	p.mutexFinesIO.Lock()
+	fin, ok := p.finesIO[pid]
+	delete(p.finesIO, pid)
+	p.mutexFinesIO.Unlock()
+
+	if ok {
+		clock.AvanzarHasta(fin)
+	}
 }
@@ -1,14 +1,11 @@
 package planificadores
 
 import (
-	"bufio"
 	"fmt"
 	"net/http"
-	"os"
-	"strconv"
-	"time"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -17,86 +14,49 @@ const (
 	PlanificadorEstadoStart = "START"
 )
 
-// PlanificadorLargoPlazo realiza las funciones correspondientes al planificador de largo plazo.
+// PlanificadorLargoPlazo realiza las funciones correspondientes al planificador de largo plazo. Los procesos que
+// llegan mientras la planificación está detenida quedan en NEW hasta que se inicie desde la consola.
 func (p *Service) PlanificadorLargoPlazo() {
-	estado := PlanificadorEstadoStop
-
-	// Lanzamos una goroutine que espera el Enter
-	go func() {
-		reader := bufio.NewReader(os.Stdin)
-		_, _ = reader.ReadString('\n') // Espera hasta que se presione Enter
-		p.CanalEnter <- struct{}{}     // Envía una señal al canal
-		estado = PlanificadorEstadoStart
-	}()
-
-	// Se queda escuchando hasta que el usuario presione la tecla ENTER por consola para iniciar el planificador
-	<-p.CanalEnter
-
-	if estado == PlanificadorEstadoStart {
-		for {
-
-			procesoNew := <-p.CanalNuevoProcesoNew
-
-			//agrego al planificador
-			switch p.LargoPlazoAlgorithm {
-			case "FIFO":
-				p.PlanificadorLargoPlazoFIFO(procesoNew)
-			case "PMCP":
-				p.PlanificadorLargoPlazoPMCP(procesoNew)
-			default:
-				p.Log.Warn("Algoritmo de largo plazo no reconocido")
-			}
+	for {
+		procesoNew := <-p.CanalNuevoProcesoNew
 
-			p.CheckearEspacioEnMemoria()
+		// La política de admisión elige entre los procesos de NEW al momento de admitir. Si necesita datos de memoria
+		// del proceso, los consulta ahora, antes de que entre a NEW
+		if politica, ok := p.PoliticaAdmision.(PoliticaAdmisionConMemoria); ok {
+			politica.AlCrear(procesoNew)
 		}
-	}
-}
-
-func (p *Service) PlanificadorLargoPlazoFIFO(proceso *internal.Proceso) {
-
-	p.mutexNewQueue.Lock()
-	//	p.Planificador.NewQueue = append([]*internal.Proceso{proceso}, p.Planificador.NewQueue...)
-	p.Planificador.NewQueue = append(p.Planificador.NewQueue, proceso)
-	p.mutexNewQueue.Unlock()
-
-}
-
-func (p *Service) PlanificadorLargoPlazoPMCP(proceso *internal.Proceso) {
-	sizeProcesoEntrante, _ := strconv.Atoi(proceso.PCB.Tamanio)
-
-	p.mutexNewQueue.Lock()
-	var yaLoAgregue = true
 
-	for i, procesoEncolado := range p.Planificador.NewQueue {
-
-		sizeProcesoEncolado, _ := strconv.Atoi(procesoEncolado.PCB.Tamanio)
-		if sizeProcesoEntrante < sizeProcesoEncolado {
-
-			// Si el proceso entrante es más chico que el encolado, lo agrego antes
-			p.Planificador.NewQueue = append(
-				p.Planificador.NewQueue[:i],
-				append([]*internal.Proceso{proceso}, p.Planificador.NewQueue[i:]...)...,
-			)
+		p.mutexNewQueue.Lock()
+		p.Planificador.NewQueue = append(p.Planificador.NewQueue, procesoNew)
+		p.mutexNewQueue.Unlock()
 
-			yaLoAgregue = false
-			break
-		}
+		p.CheckearEspacioEnMemoria()
 	}
+}
 
-	if yaLoAgregue {
-		p.Planificador.NewQueue = append([]*internal.Proceso{proceso}, p.Planificador.NewQueue...)
+func (p *Service) CheckearEspacioEnMemoria() {
+	// Con la planificación detenida no se admiten procesos
+	if !p.PlanificacionActiva() {
+		return
 	}
 
-	p.mutexNewQueue.Unlock()
+	// Si el grado de multiprogramación impide admitir procesos, los que quedan en SUSP.READY y NEW esperan por él
+	bloqueadoPorGrado := false
 
-}
+	// La política de admisión consulta a memoria antes de tomar las colas, y solo si se puede admitir algún proceso
+	if politica, ok := p.PoliticaAdmision.(PoliticaAdmisionConMemoria); ok && p.hayLugarEnMultiprogramacion() {
+		politica.AntesDeAdmitir()
+	}
 
-func (p *Service) CheckearEspacioEnMemoria() {
 	// Priorizamos los procesos suspendidos ready
 	p.mutexSuspReadyQueue.Lock()
 	i := 0
 	for i < len(p.Planificador.SuspReadyQueue) {
 		proceso := p.Planificador.SuspReadyQueue[i]
+		if !p.hayLugarEnMultiprogramacion() {
+			bloqueadoPorGrado = true
+			break
+		}
 		if p.Memoria.ConsultarEspacio(proceso.PCB.Tamanio, proceso.PCB.PID) {
 			// Si el proceso se carga en memoria, lo muevo a la cola de ready
 			// y lo elimino de la cola de suspendidos ready
@@ -104,25 +64,22 @@ func (p *Service) CheckearEspacioEnMemoria() {
 			// Remover el proceso de la cola
 			p.Planificador.SuspReadyQueue, _ = p.removerDeCola(proceso.PCB.PID, p.Planificador.SuspReadyQueue)
 
+			// Si recibió un mensaje mientras estaba swappeado, se escribe antes de que pueda volver a ejecutar
+			p.entregarMensajePendiente(proceso.PCB.PID)
+
 			if proceso.PCB.MetricasTiempo[internal.EstadoSuspReady] == nil {
 				proceso.PCB.MetricasTiempo[internal.EstadoSuspReady] = &internal.EstadoTiempo{}
 			}
 			timeSusp := proceso.PCB.MetricasTiempo[internal.EstadoSuspReady]
-			timeSusp.TiempoAcumulado = timeSusp.TiempoAcumulado + time.Since(timeSusp.TiempoInicio)
+			timeSusp.TiempoAcumulado = timeSusp.TiempoAcumulado + clock.Since(timeSusp.TiempoInicio)
 
 			// Agrego el proceso a la cola de ready
 			p.mutexReadyQueue.Lock()
-			p.Planificador.ReadyQueue = append(p.Planificador.ReadyQueue, proceso)
-			if proceso.PCB.MetricasTiempo[internal.EstadoReady] == nil {
-				proceso.PCB.MetricasTiempo[internal.EstadoReady] = &internal.EstadoTiempo{}
-			}
-			proceso.PCB.MetricasTiempo[internal.EstadoReady].TiempoInicio = time.Now()
-
-			proceso.PCB.MetricasEstado[internal.EstadoReady]++
+			p.agregarAReady(proceso)
 			p.mutexReadyQueue.Unlock()
 
 			// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-			p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado SUSP.READY al estado READY", proceso.PCB.PID))
+			p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoSuspReady, internal.EstadoReady, "")
 
 			// Enviar señal al canal de corto plazo para procesos suspendidos
 			p.Log.Debug("Enviando señal al canal de corto plazo (SUSP.READY -> READY)",
@@ -131,8 +88,8 @@ func (p *Service) CheckearEspacioEnMemoria() {
 			p.canalNuevoProcesoReady <- struct{}{}
 
 			// No incrementar i porque removimos un elemento
-		} else {
-			// Si no hay espacio, salir del loop
+		} else if !p.suspenderPorFaltaDeMemoria(proceso) {
+			// Si no hay espacio ni procesos bloqueados para suspender, salir del loop
 			break
 		}
 	}
@@ -140,46 +97,41 @@ func (p *Service) CheckearEspacioEnMemoria() {
 
 	if len(p.Planificador.SuspReadyQueue) == 0 {
 		p.mutexNewQueue.Lock()
-		i := 0
-		for i < len(p.Planificador.NewQueue) {
-			proceso := p.Planificador.NewQueue[i]
+		for len(p.Planificador.NewQueue) > 0 {
+			if !p.hayLugarEnMultiprogramacion() {
+				bloqueadoPorGrado = true
+				break
+			}
+			proceso := p.Planificador.NewQueue[p.PoliticaAdmision.ElegirProximo(p.Planificador.NewQueue)]
 			if p.Memoria.ConsultarEspacio(proceso.PCB.Tamanio, proceso.PCB.PID) {
 				// Si el proceso se carga en memoria, lo muevo a la cola de ready
 				// y lo elimino de la cola de new
 
-				// Remover el proceso de la cola usando índice
+				// Remover el proceso de la cola
 				p.Planificador.NewQueue, _ = p.removerDeCola(proceso.PCB.PID, p.Planificador.NewQueue)
 
 				if proceso.PCB.MetricasTiempo[internal.EstadoNew] == nil {
 					proceso.PCB.MetricasTiempo[internal.EstadoNew] = &internal.EstadoTiempo{}
 				}
 				timeNew := proceso.PCB.MetricasTiempo[internal.EstadoNew]
-				timeNew.TiempoAcumulado = timeNew.TiempoAcumulado + time.Since(timeNew.TiempoInicio)
-
-				if proceso.PCB.MetricasTiempo[internal.EstadoReady] == nil {
-					proceso.PCB.MetricasTiempo[internal.EstadoReady] = &internal.EstadoTiempo{}
-				}
+				timeNew.TiempoAcumulado = timeNew.TiempoAcumulado + clock.Since(timeNew.TiempoInicio)
 
 				p.Memoria.CargarProcesoEnMemoriaDeSistema(proceso.PCB.NombreArchivo, proceso.PCB.PID)
 
 				// Primero agrego el proceso a la cola de ready
 				p.mutexReadyQueue.Lock()
-				p.Planificador.ReadyQueue = append(p.Planificador.ReadyQueue, proceso)
-				proceso.PCB.MetricasTiempo[internal.EstadoReady].TiempoInicio = time.Now()
-				proceso.PCB.MetricasEstado[internal.EstadoReady]++
+				p.agregarAReady(proceso)
 				p.mutexReadyQueue.Unlock()
 
 				// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-				p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado NEW al estado READY", proceso.PCB.PID))
+				p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoNew, internal.EstadoReady, "")
 
 				// Luego, envío la señal para que el planificador de corto plazo pueda ejecutar el proceso
 				p.Log.Debug("Enviando señal al canal de corto plazo",
 					log.IntAttr("pid", proceso.PCB.PID))
 
 				p.canalNuevoProcesoReady <- struct{}{}
-
-				// No incrementar i porque removimos un elemento
-			} else {
+			} else if !p.suspenderPorFaltaDeMemoria(proceso) {
 				p.Log.Debug("No hay espacio en memoria para el proceso",
 					log.IntAttr("pid", proceso.PCB.PID))
 				break
@@ -187,7 +139,16 @@ func (p *Service) CheckearEspacioEnMemoria() {
 		}
 		p.mutexNewQueue.Unlock()
 	}
+
+	esperando := 0
+	if bloqueadoPorGrado {
+		p.mutexNewQueue.RLock()
+		esperando = len(p.Planificador.SuspReadyQueue) + len(p.Planificador.NewQueue)
+		p.mutexNewQueue.RUnlock()
+	}
 	p.mutexSuspReadyQueue.Unlock()
+
+	p.registrarEsperaMultiprogramacion(esperando)
 }
 
 func (p *Service) FinalizarProceso(pid int) {
@@ -230,7 +191,7 @@ func (p *Service) FinalizarProceso(pid int) {
 			break
 		}
 		proc.PCB.MetricasTiempo[internal.EstadoExec].TiempoAcumulado +=
-			time.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
+			clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
 	}
 	p.mutexExecQueue.Unlock()
 
@@ -246,43 +207,28 @@ func (p *Service) FinalizarProceso(pid int) {
 	if proceso.PCB.MetricasTiempo[internal.EstadoExit] == nil {
 		proceso.PCB.MetricasTiempo[internal.EstadoExit] = &internal.EstadoTiempo{}
 	}
-	proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoInicio = time.Now()
+	proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoInicio = clock.Now()
 
 	// 6. Cambiar el estado del proceso a EXIT (las métricas de EXEC ya están actualizadas por actualizarRafagaAnterior)
-	proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoAcumulado = time.Since(proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoInicio)
+	proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoAcumulado = clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoInicio)
 	proceso.PCB.MetricasEstado[internal.EstadoExec]++
 	proceso.PCB.MetricasEstado[internal.EstadoExit]++
 
 	// 7. Loguear métricas
 	//Log obligatorio: Cambio de estado
 	// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-	p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado EXEC al estado EXIT", proceso.PCB.PID))
+	p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoExec, internal.EstadoExit, "")
 
 	//Log obligatorio: Finalización de proceso
 	//"## (<PID>) - Finaliza el proceso"
 	p.Log.Info(fmt.Sprintf("## (%d) Finaliza el proceso", proceso.PCB.PID))
 
-	// Log obligatorio: Métricas de Estado
-	//"## (<PID>) - Métricas de estado: NEW (NEW_COUNT) (NEW_TIME), READY (READY_COUNT) (READY_TIME), …"
-	p.Log.Info(fmt.Sprintf("## (%d) - Métricas de estado: NEW %d %d, READY %d %d, "+
-		"EXEC %d %d, BLOCKED %d %d, SUSP. BLOCKED %d %d, SUSP. READY %d %d, EXIT %d %d",
-		proceso.PCB.PID,
-		proceso.PCB.MetricasEstado[internal.EstadoNew],
-		proceso.PCB.MetricasTiempo[internal.EstadoNew].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoReady],
-		proceso.PCB.MetricasTiempo[internal.EstadoReady].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoExec],
-		proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoBloqueado],
-		proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoSuspBloqueado],
-		proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoSuspReady],
-		proceso.PCB.MetricasTiempo[internal.EstadoSuspReady].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoExit],
-		proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoAcumulado.Milliseconds(),
-	),
-	)
+	// Log obligatorio: Métricas de Estado, y registro en el reporte de finalización
+	p.registrarFinalizacion(proceso)
+	p.olvidarEnScheduler(proceso)
+	p.sacarDelArbol(proceso.PCB)
+	p.liberarRecursos(proceso.PCB.PID)
+	p.liberarMailboxes(proceso.PCB.PID)
 
 	// 8. Checkear si hay procesos suspendidos que puedan volver a memoria
 	p.CheckearEspacioEnMemoria()
@@ -303,7 +249,7 @@ func (p *Service) FinalizarProcesoEnCualquierCola(pid int) {
 		// Actualizar métricas de tiempo
 		if proceso.PCB.MetricasTiempo[cola] != nil {
 			proceso.PCB.MetricasTiempo[cola].TiempoAcumulado +=
-				time.Since(proceso.PCB.MetricasTiempo[cola].TiempoInicio)
+				clock.Since(proceso.PCB.MetricasTiempo[cola].TiempoInicio)
 		}
 
 		switch cola {
@@ -320,10 +266,12 @@ func (p *Service) FinalizarProcesoEnCualquierCola(pid int) {
 		case internal.EstadoReady:
 			p.mutexReadyQueue.Lock()
 			p.Planificador.ReadyQueue, _ = p.removerDeCola(pid, p.Planificador.ReadyQueue)
+			p.sacarDeColasCPU(pid)
 			p.mutexReadyQueue.Unlock()
 		case internal.EstadoBloqueado:
 			p.mutexBlockQueue.Lock()
 			p.Planificador.BlockQueue, _ = p.removerDeCola(pid, p.Planificador.BlockQueue)
+			p.Temporizadores.Cancelar(pid)
 			p.mutexBlockQueue.Unlock()
 		case internal.EstadoSuspBloqueado:
 			p.mutexSuspBlockQueue.Lock()
@@ -365,37 +313,23 @@ func (p *Service) FinalizarProcesoEnCualquierCola(pid int) {
 	if proceso.PCB.MetricasTiempo[internal.EstadoExit] == nil {
 		proceso.PCB.MetricasTiempo[internal.EstadoExit] = &internal.EstadoTiempo{}
 	}
-	proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoInicio = time.Now()
+	proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoInicio = clock.Now()
 
 	//Log obligatorio: Cambio de estado
-	p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado %s al estado EXIT", proceso.PCB.PID, cola))
+	p.registrarCambioEstado(proceso.PCB.PID, cola, internal.EstadoExit, "")
 
 	//Log obligatorio: Finalización de proceso
 	p.Log.Info(fmt.Sprintf("## (%d) Finaliza el proceso", proceso.PCB.PID))
 
 	proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoAcumulado +=
-		time.Since(proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoInicio)
-
-	// Log obligatorio: Métricas de Estado
-	p.Log.Info(fmt.Sprintf("## (%d) - Métricas de estado: NEW %d %d, READY %d %d, "+
-		"EXEC %d %d, BLOCKED %d %d, SUSP. BLOCKED %d %d, SUSP. READY %d %d, EXIT %d %d",
-		proceso.PCB.PID,
-		proceso.PCB.MetricasEstado[internal.EstadoNew],
-		proceso.PCB.MetricasTiempo[internal.EstadoNew].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoReady],
-		proceso.PCB.MetricasTiempo[internal.EstadoReady].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoExec],
-		proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoBloqueado],
-		proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoSuspBloqueado],
-		proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoSuspReady],
-		proceso.PCB.MetricasTiempo[internal.EstadoSuspReady].TiempoAcumulado.Milliseconds(),
-		proceso.PCB.MetricasEstado[internal.EstadoExit],
-		proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoAcumulado.Milliseconds(),
-	),
-	)
+		clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoExit].TiempoInicio)
+
+	// Log obligatorio: Métricas de Estado, y registro en el reporte de finalización
+	p.registrarFinalizacion(proceso)
+	p.olvidarEnScheduler(proceso)
+	p.sacarDelArbol(proceso.PCB)
+	p.liberarRecursos(proceso.PCB.PID)
+	p.liberarMailboxes(proceso.PCB.PID)
 
 	proceso.PCB = nil // Liberar referencia al proceso
 	proceso = nil     // Liberar referencia al proceso
@@ -0,0 +1,353 @@
+package planificadores
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+const (
+	RecursoSend = "SEND" // Recurso de los bloqueos por SEND a un mailbox lleno
+	RecursoRecv = "RECV" // Recurso de los bloqueos por RECV a un mailbox vacío
+
+	CapacidadMailboxPorDefecto = 8 // Mensajes que entran en un mailbox si no se configura mailbox_capacity
+)
+
+var (
+	ErrMailboxAjeno       = errors.New("el mailbox de un proceso solo lo puede leer ese proceso")
+	ErrMailboxInexistente = errors.New("el proceso destinatario no existe")
+)
+
+// mailbox es una cola acotada de mensajes. Se crea al usarlo y se borra apenas queda sin mensajes ni procesos
+// esperando, así que solo ocupan memoria los mailboxes en uso. Los que se llaman como un PID son la casilla de ese
+// proceso y también se borran cuando finaliza.
+type mailbox struct {
+	nombre     string
+	mensajes   []string
+	receptores []receptorMensaje // Procesos bloqueados por RECV, en orden de llegada
+	emisores   []emisorMensaje   // Procesos bloqueados por SEND con el mailbox lleno, en orden de llegada
+}
+
+type receptorMensaje struct {
+	proceso   *internal.Proceso
+	direccion int // Dirección lógica donde se escribe el mensaje
+}
+
+type emisorMensaje struct {
+	proceso *internal.Proceso
+	mensaje string
+}
+
+// entregaPendiente es un mensaje que recibió un proceso swappeado: se escribe cuando vuelve a memoria principal
+type entregaPendiente struct {
+	mensaje   string
+	direccion int
+}
+
+// ConfigurarMailboxes define cuántos mensajes entran en cada mailbox (mailbox_capacity) antes de bloquear a quien
+// hace SEND
+func (p *Service) ConfigurarMailboxes(capacidad int) {
+	if capacidad <= 0 {
+		capacidad = CapacidadMailboxPorDefecto
+	}
+
+	p.mutexMailboxes.Lock()
+	p.capacidadMailbox = capacidad
+	p.mutexMailboxes.Unlock()
+}
+
+// DepositarMensaje atiende SEND mientras el proceso sigue ejecutando: si alguien espera en el mailbox le entrega el
+// mensaje, y si no lo encola. Devuelve false si el mailbox está lleno; la CPU devuelve el contexto para que se
+// bloquee al proceso con EnviarMensaje.
+func (p *Service) DepositarMensaje(pid int, destino, mensaje string) (bool, error) {
+	nombre, existe := p.nombreMailbox(destino)
+	if !existe {
+		return false, fmt.Errorf("%w: %s", ErrMailboxInexistente, nombre)
+	}
+
+	p.mutexMailboxes.Lock()
+	mb := p.mailbox(nombre)
+	receptor, entregado := p.depositar(mb, mensaje)
+	p.olvidarSiVacio(mb)
+	p.mutexMailboxes.Unlock()
+
+	if !entregado {
+		return false, nil
+	}
+
+	p.Log.Info(fmt.Sprintf("## (%d) - SEND: Envía un mensaje a %s", pid, nombre))
+	p.entregarMensaje(receptor, mensaje, nombre)
+	return true, nil
+}
+
+// EnviarMensaje atiende SEND cuando el proceso ya devolvió la CPU: lo bloquea hasta que haya lugar en el mailbox. Si
+// mientras tanto se liberó lugar, deposita el mensaje y se desbloquea en el momento.
+func (p *Service) EnviarMensaje(pid int, destino, mensaje string) error {
+	nombre, existe := p.nombreMailbox(destino)
+	if !existe {
+		return fmt.Errorf("%w: %s", ErrMailboxInexistente, nombre)
+	}
+
+	proceso, err := p.bloquear(pid, RecursoSend, nil)
+	if err != nil {
+		return err
+	}
+
+	// Nadie toma mensajes del mailbox sin el mutex, así que el lugar no se puede liberar sin que se lo vea acá
+	p.mutexMailboxes.Lock()
+	mb := p.mailbox(nombre)
+	receptor, entregado := p.depositar(mb, mensaje)
+	if !entregado {
+		mb.emisores = append(mb.emisores, emisorMensaje{proceso: proceso, mensaje: mensaje})
+	}
+	p.olvidarSiVacio(mb)
+	p.mutexMailboxes.Unlock()
+
+	if !entregado {
+		p.Log.Info(fmt.Sprintf("## (%d) - Bloqueado por SEND: mailbox %s lleno", pid, nombre))
+		return nil
+	}
+
+	p.Log.Info(fmt.Sprintf("## (%d) - SEND: Envía un mensaje a %s", pid, nombre))
+	p.entregarMensaje(receptor, mensaje, nombre)
+	p.ManejarFinIO(proceso)
+	return nil
+}
+
+// Recibir atiende RECV: bloquea al proceso hasta que llegue un mensaje al mailbox y lo escribe en su memoria a partir
+// de la dirección lógica dada. Si ya había un mensaje, lo recibe y se desbloquea en el momento.
+// IMPORTANTE: Siempre llega con el contexto, para que la CPU ya haya bajado la caché del proceso a memoria
+func (p *Service) Recibir(pid int, origen string, direccion int) error {
+	nombre := origen
+	if duenio, err := strconv.Atoi(origen); err == nil {
+		if duenio != pid {
+			return fmt.Errorf("%w: PID %d, mailbox %s", ErrMailboxAjeno, pid, origen)
+		}
+		nombre = strconv.Itoa(duenio)
+	}
+
+	proceso, err := p.bloquear(pid, RecursoRecv, nil)
+	if err != nil {
+		return err
+	}
+	receptor := receptorMensaje{proceso: proceso, direccion: direccion}
+
+	p.mutexMailboxes.Lock()
+	mb := p.mailbox(nombre)
+	mensaje, emisor, ok := tomarMensaje(mb)
+	if !ok {
+		mb.receptores = append(mb.receptores, receptor)
+		p.mutexMailboxes.Unlock()
+
+		p.Log.Info(fmt.Sprintf("## (%d) - Bloqueado por RECV: %s", pid, nombre))
+		return nil
+	}
+	p.olvidarSiVacio(mb)
+	p.mutexMailboxes.Unlock()
+
+	p.entregarMensaje(&receptor, mensaje, nombre)
+
+	if emisor != nil {
+		p.Log.Info(fmt.Sprintf("## (%d) - SEND: Hay lugar en %s, deposita el mensaje y se desbloquea",
+			emisor.proceso.PCB.PID, nombre))
+		p.ManejarFinIO(emisor.proceso)
+	}
+
+	return nil
+}
+
+// nombreMailbox normaliza el destino de SEND o el origen de RECV. Un número es la casilla de ese PID, que existe
+// mientras el proceso no finalice.
+func (p *Service) nombreMailbox(destino string) (string, bool) {
+	pid, err := strconv.Atoi(destino)
+	if err != nil {
+		return destino, true
+	}
+
+	proceso, _ := p.BuscarProcesoEnCualquierCola(pid)
+	return strconv.Itoa(pid), proceso != nil
+}
+
+// mailbox devuelve el mailbox con el nombre dado, creándolo si no existe
+// IMPORTANTE: Se llama con mutexMailboxes bloqueado
+func (p *Service) mailbox(nombre string) *mailbox {
+	mb, ok := p.mailboxes[nombre]
+	if !ok {
+		mb = &mailbox{nombre: nombre}
+		p.mailboxes[nombre] = mb
+	}
+	return mb
+}
+
+// olvidarSiVacio borra el mailbox si no le quedan mensajes ni procesos esperando. Si se lo vuelve a usar, se crea de
+// nuevo vacío, igual que estaba.
+// IMPORTANTE: Se llama con mutexMailboxes bloqueado
+func (p *Service) olvidarSiVacio(mb *mailbox) {
+	if len(mb.mensajes) == 0 && len(mb.receptores) == 0 && len(mb.emisores) == 0 {
+		delete(p.mailboxes, mb.nombre)
+	}
+}
+
+// tomarMensaje saca el mensaje más antiguo del mailbox. Si había un emisor bloqueado por el mailbox lleno, deposita
+// su mensaje en el lugar liberado y lo devuelve para desbloquearlo. Devuelve false si el mailbox está vacío.
+// IMPORTANTE: Se llama con mutexMailboxes bloqueado
+func tomarMensaje(mb *mailbox) (string, *emisorMensaje, bool) {
+	if len(mb.mensajes) == 0 {
+		return "", nil, false
+	}
+
+	mensaje := mb.mensajes[0]
+	mb.mensajes = mb.mensajes[1:]
+
+	var emisor *emisorMensaje
+	if len(mb.emisores) > 0 {
+		primero := mb.emisores[0]
+		emisor = &primero
+		mb.emisores = mb.emisores[1:]
+		mb.mensajes = append(mb.mensajes, emisor.mensaje)
+	}
+
+	return mensaje, emisor, true
+}
+
+// depositar le da el mensaje al primer proceso que espera en el mailbox o, si no hay ninguno, lo encola. Devuelve el
+// receptor al que hay que entregarle el mensaje (si lo hay) y false si el mailbox estaba lleno.
+// IMPORTANTE: Se llama con mutexMailboxes bloqueado
+func (p *Service) depositar(mb *mailbox, mensaje string) (*receptorMensaje, bool) {
+	if len(mb.receptores) > 0 {
+		receptor := mb.receptores[0]
+		mb.receptores = mb.receptores[1:]
+		return &receptor, true
+	}
+
+	if len(mb.mensajes) >= p.capacidadMailbox {
+		return nil, false
+	}
+
+	mb.mensajes = append(mb.mensajes, mensaje)
+	return nil, true
+}
+
+// entregarMensaje escribe el mensaje en la memoria del proceso que hizo RECV y lo desbloquea. Si está swappeado, el
+// mensaje queda pendiente hasta que vuelva a memoria principal.
+func (p *Service) entregarMensaje(receptor *receptorMensaje, mensaje, nombre string) {
+	if receptor == nil {
+		return
+	}
+	pid := receptor.proceso.PCB.PID
+
+	// Se marca al proceso para que no se lo suspenda mientras se escribe, sin retener BlockQueue durante la escritura
+	p.mutexBlockQueue.Lock()
+	var enMemoria bool
+	for _, bloqueado := range p.Planificador.BlockQueue {
+		if bloqueado != nil && bloqueado.PCB != nil && bloqueado.PCB.PID == pid {
+			enMemoria = true
+			p.entregando[pid] = true
+			break
+		}
+	}
+	p.mutexBlockQueue.Unlock()
+
+	var err error
+	if enMemoria {
+		err = p.Memoria.EscribirEnProceso(pid, receptor.direccion, mensaje)
+
+		p.mutexBlockQueue.Lock()
+		delete(p.entregando, pid)
+		p.mutexBlockQueue.Unlock()
+	} else {
+		p.mutexMailboxes.Lock()
+		p.entregasPendientes[pid] = entregaPendiente{mensaje: mensaje, direccion: receptor.direccion}
+		p.mutexMailboxes.Unlock()
+	}
+
+	if err != nil {
+		p.falloEntrega(pid, err)
+		return
+	}
+
+	p.Log.Info(fmt.Sprintf("## (%d) - RECV: Recibe un mensaje de %s, se desbloquea", pid, nombre))
+	p.ManejarFinIO(receptor.proceso)
+}
+
+// entregarMensajePendiente escribe el mensaje que recibió un proceso mientras estaba swappeado. Si no se puede
+// escribir, el proceso se finaliza igual que con un mensaje entregado en BLOCKED.
+// IMPORTANTE: Se llama cuando el proceso ya volvió a memoria principal y antes de que pase a READY
+func (p *Service) entregarMensajePendiente(pid int) {
+	p.mutexMailboxes.Lock()
+	entrega, ok := p.entregasPendientes[pid]
+	delete(p.entregasPendientes, pid)
+	p.mutexMailboxes.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if err := p.Memoria.EscribirEnProceso(pid, entrega.direccion, entrega.mensaje); err != nil {
+		p.falloEntrega(pid, err)
+		return
+	}
+
+	p.Log.Debug("Mensaje pendiente escrito al volver a memoria",
+		log.IntAttr("pid", pid),
+		log.IntAttr("direccion", entrega.direccion),
+	)
+}
+
+// falloEntrega manda a EXIT a un proceso en cuya memoria no se pudo escribir el mensaje recibido, igual que si la
+// CPU hubiera fallado al escribir en esa dirección
+func (p *Service) falloEntrega(pid int, err error) {
+	p.Log.Warn("No se pudo escribir el mensaje en la memoria del proceso, se finaliza",
+		log.ErrAttr(err),
+		log.IntAttr("pid", pid),
+	)
+
+	go p.FinalizarProcesoEnCualquierCola(pid)
+}
+
+func (p *Service) descartarMensaje(pid int, destino string) {
+	p.Log.Info(fmt.Sprintf("## (%d) - SEND: El proceso %s finalizó, se descarta el mensaje", pid, destino))
+}
+
+// liberarMailboxes se llama cuando un proceso finaliza: lo saca de los mailboxes donde esperaba y borra su casilla.
+// Los procesos bloqueados enviándole un mensaje se desbloquean y el mensaje se descarta.
+func (p *Service) liberarMailboxes(pid int) {
+	p.mutexMailboxes.Lock()
+	delete(p.entregasPendientes, pid)
+	casilla := p.mailboxes[strconv.Itoa(pid)]
+	delete(p.mailboxes, strconv.Itoa(pid))
+	for _, mb := range p.mailboxes {
+		mb.receptores = quitarProcesoDeMailbox(mb.receptores, pid, func(r receptorMensaje) *internal.Proceso { return r.proceso })
+		mb.emisores = quitarProcesoDeMailbox(mb.emisores, pid, func(e emisorMensaje) *internal.Proceso { return e.proceso })
+		p.olvidarSiVacio(mb)
+	}
+	p.mutexMailboxes.Unlock()
+
+	if casilla == nil {
+		return
+	}
+
+	if len(casilla.mensajes) > 0 {
+		p.Log.Debug("Se descartan los mensajes sin leer del proceso finalizado",
+			log.IntAttr("pid", pid),
+			log.IntAttr("mensajes", len(casilla.mensajes)),
+		)
+	}
+	for _, emisor := range casilla.emisores {
+		p.descartarMensaje(emisor.proceso.PCB.PID, casilla.nombre)
+		p.ManejarFinIO(emisor.proceso)
+	}
+}
+
+func quitarProcesoDeMailbox[T any](cola []T, pid int, proceso func(T) *internal.Proceso) []T {
+	resultado := cola[:0]
+	for _, elemento := range cola {
+		if pcb := proceso(elemento).PCB; pcb == nil || pcb.PID != pid {
+			resultado = append(resultado, elemento)
+		}
+	}
+	return resultado
+}
@@ -0,0 +1,118 @@
+package planificadores
+
+import (
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+)
+
+func TestMailboxes_DepositarYTomar(t *testing.T) {
+	proceso := func(pid int) *internal.Proceso {
+		return &internal.Proceso{PCB: &internal.PCB{PID: pid}}
+	}
+
+	tests := []struct {
+		nombre    string
+		enviados  []string // Mensajes depositados en orden, con capacidad 2
+		emisores  []string // Mensajes de los procesos bloqueados por SEND con el mailbox lleno
+		recibidos []string // Mensajes que toman los RECV sucesivos hasta vaciar el mailbox
+		rechazado []bool   // Si cada depósito encontró el mailbox lleno
+		liberados []int    // PIDs de los emisores que se desbloquean al liberarse lugar, en orden
+	}{
+		{
+			nombre:    "se reciben en el orden en que se enviaron",
+			enviados:  []string{"A", "B"},
+			recibidos: []string{"A", "B"},
+			rechazado: []bool{false, false},
+		},
+		{
+			nombre:    "con el mailbox lleno se rechaza el depósito",
+			enviados:  []string{"A", "B", "C"},
+			recibidos: []string{"A", "B"},
+			rechazado: []bool{false, false, true},
+		},
+		{
+			nombre:    "cada lugar liberado lo ocupa el primer emisor bloqueado",
+			enviados:  []string{"A", "B"},
+			emisores:  []string{"C", "D"},
+			recibidos: []string{"A", "B", "C", "D"},
+			rechazado: []bool{false, false},
+			liberados: []int{10, 11},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			p := &Service{capacidadMailbox: 2}
+			mb := &mailbox{nombre: "M"}
+
+			var rechazado []bool
+			for _, mensaje := range tt.enviados {
+				_, entregado := p.depositar(mb, mensaje)
+				rechazado = append(rechazado, !entregado)
+			}
+			for i, mensaje := range tt.emisores {
+				mb.emisores = append(mb.emisores, emisorMensaje{proceso: proceso(10 + i), mensaje: mensaje})
+			}
+
+			var recibidos []string
+			var liberados []int
+			for {
+				mensaje, emisor, ok := tomarMensaje(mb)
+				if !ok {
+					break
+				}
+				recibidos = append(recibidos, mensaje)
+				if emisor != nil {
+					liberados = append(liberados, emisor.proceso.PCB.PID)
+				}
+			}
+
+			if !reflect.DeepEqual(rechazado, tt.rechazado) {
+				t.Errorf("rechazados = %v, se esperaba %v", rechazado, tt.rechazado)
+			}
+			if !reflect.DeepEqual(recibidos, tt.recibidos) {
+				t.Errorf("recibidos = %v, se esperaba %v", recibidos, tt.recibidos)
+			}
+			if !reflect.DeepEqual(liberados, tt.liberados) {
+				t.Errorf("emisores liberados = %v, se esperaba %v", liberados, tt.liberados)
+			}
+		})
+	}
+}
+
+func TestMailboxes_ReceptorSwappeado(t *testing.T) {
+	receptor := &internal.Proceso{PCB: &internal.PCB{PID: 4}}
+	p := &Service{
+		Planificador:         &Planificador{SuspBlockQueue: []*internal.Proceso{receptor}},
+		Log:                  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		mutexBlockQueue:      &sync.RWMutex{},
+		mutexSuspBlockQueue:  &sync.RWMutex{},
+		mutexMailboxes:       &sync.Mutex{},
+		mailboxes:            make(map[string]*mailbox),
+		entregasPendientes:   make(map[int]entregaPendiente),
+		entregando:           make(map[int]bool),
+		capacidadMailbox:     2,
+		suspensionesManuales: map[int]*suspensionManual{4: {esperaEvento: true}}, // Queda en SUSP.BLOCKED
+	}
+	p.mailboxes["M"] = &mailbox{nombre: "M", receptores: []receptorMensaje{{proceso: receptor, direccion: 32}}}
+
+	entregado, err := p.DepositarMensaje(1, "M", "hola")
+	if err != nil || !entregado {
+		t.Fatalf("DepositarMensaje() = %v, %v; se esperaba que se entregue", entregado, err)
+	}
+
+	// El receptor está swappeado: el mensaje se escribe cuando vuelva a memoria principal
+	if pendiente := p.entregasPendientes[4]; pendiente != (entregaPendiente{mensaje: "hola", direccion: 32}) {
+		t.Errorf("entrega pendiente = %+v, se esperaba el mensaje en la dirección 32", pendiente)
+	}
+
+	// El mailbox quedó sin mensajes ni procesos esperando, así que se borra
+	if _, ok := p.mailboxes["M"]; ok {
+		t.Errorf("el mailbox vacío no se borró")
+	}
+}
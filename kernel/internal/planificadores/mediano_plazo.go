@@ -1,69 +1,20 @@
 package planificadores
 
 import (
-	"fmt"
 	"strings"
-	"time"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
+// SuspenderProcesoBloqueado avisa a la política de suspensión cada vez que un proceso pasa a BLOCKED
 func (p *Service) SuspenderProcesoBloqueado() {
 	for {
 		//La funcion espera que entre un proceso en la cola de blocked
 		proceso := <-p.CanalNuevoProcBlocked
 
-		go func() {
-			//Busco tiempo de espera para pasar a SuspendedBlocked
-			//del archivo de configuración
-			tiempoEspera := p.MedianoPlazoConfig.SuspensionTime
-			//Espero que pase el tiempo determinado
-			time.Sleep(time.Duration(tiempoEspera) * time.Millisecond)
-
-			//Si el proceso sigue bloqueado, lo suspendemos
-			//p.mutexBlockQueue.Lock()
-			//sigueBloqueado := estaEnCola(proceso, p.Planificador.BlockQueue)
-			if proceso != nil && proceso.PCB != nil {
-				sigueBloqueado := p.BuscarProcesoEnCola(proceso.PCB.PID, "blocked")
-				sigueBloqueadoEnSusp := p.BuscarProcesoEnCola(proceso.PCB.PID, "suspended_blocked")
-				//TO DO: SI ROMPE COMENTARLA
-				if sigueBloqueadoEnSusp != nil {
-					return
-				}
-				if sigueBloqueado != nil {
-					//Mover de blocked a suspended blocked
-					p.mutexBlockQueue.Lock()
-					p.removerDeCola(proceso.PCB.PID, p.Planificador.BlockQueue)
-					p.mutexBlockQueue.Unlock()
-
-					p.mutexSuspBlockQueue.Lock()
-					p.Planificador.SuspBlockQueue = append(p.Planificador.SuspBlockQueue, proceso)
-					p.mutexSuspBlockQueue.Unlock()
-
-					//Actualizar métricas
-					tiempo := proceso.PCB.MetricasTiempo[internal.EstadoBloqueado]
-					tiempo.TiempoAcumulado += time.Since(tiempo.TiempoInicio)
-
-					if proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado] == nil {
-						proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado] = &internal.EstadoTiempo{}
-					}
-					proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado].TiempoInicio = time.Now()
-					proceso.PCB.MetricasEstado[internal.EstadoSuspBloqueado]++
-
-					//Log obligatorio: Cambio de estado
-					// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-					p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado BLOCKED al estado SUSP.BLOCKED", proceso.PCB.PID))
-
-					//Notificar a memoria que debe swappear
-					go p.avisarAMemoriaSwap(proceso)
-
-					//Intentar traer procesos desde SUSP READY o NEW a memoria
-					p.CheckearEspacioEnMemoria()
-
-				}
-			} //p.mutexBlockQueue.Unlock()
-		}()
+		p.PoliticaSuspension.AlBloquearse(proceso)
 	}
 }
 
@@ -78,6 +29,12 @@ func (p *Service) ManejarFinIO(proceso *internal.Proceso) {
 	//p.mutexSuspBlockQueue.Lock()
 	//estabaSuspendido := estaEnCola(proceso, p.Planificador.SuspBlockQueue)
 	estabaSuspendido := p.BuscarProcesoEnCola(proceso.PCB.PID, "suspended_blocked")
+	if estabaSuspendido != nil && p.retenerSuspensionManual(proceso.PCB.PID) {
+		p.Log.Debug("Fin de IO de un proceso suspendido desde la API, queda en SUSP.BLOCKED hasta que se lo reanude",
+			log.IntAttr("pid", proceso.PCB.PID),
+		)
+		return
+	}
 	if estabaSuspendido != nil {
 		var removido bool
 		p.mutexSuspBlockQueue.Lock()
@@ -97,17 +54,17 @@ func (p *Service) ManejarFinIO(proceso *internal.Proceso) {
 
 		//Actualizar métricas
 		tiempo := proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado]
-		tiempo.TiempoAcumulado += time.Since(tiempo.TiempoInicio)
+		tiempo.TiempoAcumulado += clock.Since(tiempo.TiempoInicio)
 
 		if proceso.PCB.MetricasTiempo[internal.EstadoSuspReady] == nil {
 			proceso.PCB.MetricasTiempo[internal.EstadoSuspReady] = &internal.EstadoTiempo{}
 		}
-		proceso.PCB.MetricasTiempo[internal.EstadoSuspReady].TiempoInicio = time.Now()
+		proceso.PCB.MetricasTiempo[internal.EstadoSuspReady].TiempoInicio = clock.Now()
 		proceso.PCB.MetricasEstado[internal.EstadoSuspReady]++
 
 		//Log obligatorio: Cambio de estado
 		// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-		p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado SUSP.BLOCKED al estado SUSP.READY", proceso.PCB.PID))
+		p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoSuspBloqueado, internal.EstadoSuspReady, "")
 
 		// Checkear si hay espacio en memoria para traer procesos suspendidos
 		p.CheckearEspacioEnMemoria()
@@ -123,26 +80,22 @@ func (p *Service) ManejarFinIO(proceso *internal.Proceso) {
 				log.IntAttr("pid", proceso.PCB.PID),
 			)
 		}
+		p.Temporizadores.Cancelar(proceso.PCB.PID) // Terminó el bloqueo, ya no se lo suspende por tiempo
 		if proceso.PCB.MetricasTiempo[internal.EstadoBloqueado] == nil {
 			proceso.PCB.MetricasTiempo[internal.EstadoBloqueado] = &internal.EstadoTiempo{
 				TiempoAcumulado: 0,
 			}
 		}
-		proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoAcumulado += time.Since(proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoInicio)
+		proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoAcumulado += clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoBloqueado].TiempoInicio)
 		p.mutexBlockQueue.Unlock()
 
 		p.mutexReadyQueue.Lock()
-		p.Planificador.ReadyQueue = append(p.Planificador.ReadyQueue, proceso)
-		if proceso.PCB.MetricasTiempo[internal.EstadoReady] == nil {
-			proceso.PCB.MetricasTiempo[internal.EstadoReady] = &internal.EstadoTiempo{}
-		}
-		proceso.PCB.MetricasTiempo[internal.EstadoReady].TiempoInicio = time.Now()
-		proceso.PCB.MetricasEstado[internal.EstadoReady]++
+		p.agregarAReady(proceso)
 		p.mutexReadyQueue.Unlock()
 
 		//Log obligatorio: Cambio de estado
 		// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
-		p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado BLOCKED al estado READY", proceso.PCB.PID))
+		p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoBloqueado, internal.EstadoReady, "")
 
 		// Notificar planificador corto plazo
 		p.canalNuevoProcesoReady <- struct{}{}
@@ -0,0 +1,41 @@
+package planificadores
+
+import "github.com/sisoputnfrba/tp-golang/utils/metricas"
+
+const (
+	MotivoDesalojoQuantum   = "quantum"   // Desalojo por fin de quantum (RR, VRR, MLFQ)
+	MotivoDesalojoAlgoritmo = "algoritmo" // Desalojo por un proceso más prioritario (SRT, prioridades)
+)
+
+var (
+	metricaProcesosEnCola = metricas.Default.Gauge("kernel_procesos_en_cola",
+		"Procesos en cada cola del planificador", "estado")
+	metricaTransiciones = metricas.Default.Contador("kernel_transiciones_total",
+		"Cambios de estado de los procesos", "desde", "hacia")
+	metricaSyscalls = metricas.Default.Contador("kernel_syscalls_total",
+		"Syscalls solicitadas por los procesos, por tipo", "syscall")
+	metricaDesalojos = metricas.Default.Contador("kernel_desalojos_total",
+		"Procesos desalojados de la CPU, por motivo", "motivo")
+	metricaSuspensiones = metricas.Default.Contador("kernel_suspensiones_total",
+		"Procesos bloqueados suspendidos por el planificador de mediano plazo, por política", "politica")
+	metricaDeadlocks = metricas.Default.Contador("kernel_deadlocks_total",
+		"Ciclos de espera entre recursos del kernel detectados")
+)
+
+// registrarMetricas expone el largo de cada cola del planificador, que se calcula al consultar /metrics. Los procesos
+// finalizados se cuentan en kernel_transiciones_total.
+func (p *Service) registrarMetricas() {
+	for _, c := range p.colasConsultables() {
+		metricaProcesosEnCola.SetFunc(func() float64 {
+			c.mutex.RLock()
+			defer c.mutex.RUnlock()
+
+			return float64(len(*c.cola))
+		}, string(c.estado))
+	}
+}
+
+// ContarSyscall suma una syscall del tipo dado a las métricas
+func ContarSyscall(instruccion string) {
+	metricaSyscalls.Inc(instruccion)
+}
@@ -0,0 +1,189 @@
+package planificadores
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// schedulerMLFQ planifica los procesos con colas multinivel con retroalimentación. Todos los procesos comparten la
+// ReadyQueue, pero cada uno tiene un nivel (0 es el de mayor prioridad) con su propio quantum:
+// * Se elige siempre al proceso de menor nivel, y entre los del mismo nivel, al que llegó primero.
+// * Si un proceso consume todo su quantum, baja un nivel.
+// * Si un proceso se bloquea antes de consumir su quantum (IO, DUMP_MEMORY), sube un nivel.
+// * Cada cierto tiempo (boost) todos los procesos vuelven al nivel 0.
+// Un proceso que llega a READY con un nivel menor al de alguno en ejecución lo desaloja.
+type schedulerMLFQ struct {
+	service *Service
+}
+
+// Iniciar lanza el boost periódico, si está configurado
+func (s *schedulerMLFQ) Iniciar() {
+	if s.cantidadNiveles() == 0 {
+		s.service.Log.Warn("Niveles MLFQ no configurados, se usará un único nivel sin quantum")
+		return
+	}
+
+	if s.service.MLFQConfig.Boost > 0 {
+		go s.boost()
+	}
+}
+
+// ElegirProximo ordena la cola de ReadyQueue por nivel ascendente, respetando el orden de llegada dentro de cada
+// nivel, y devuelve el primero
+func (s *schedulerMLFQ) ElegirProximo(ready []*internal.Proceso) *internal.Proceso {
+	ordenarEstable(ready, func(a, b *internal.Proceso) bool {
+		return a.PCB.NivelMLFQ < b.PCB.NivelMLFQ
+	})
+
+	return ready[0]
+}
+
+func (s *schedulerMLFQ) AlIngresarReady(*internal.Proceso) {}
+
+// AlBloquearse sube un nivel al proceso que se bloqueó antes de consumir su quantum
+func (s *schedulerMLFQ) AlBloquearse(proceso *internal.Proceso) {
+	if proceso.PCB.NivelMLFQ > 0 {
+		proceso.PCB.NivelMLFQ--
+	}
+
+	s.service.Log.Debug("Proceso se bloquea antes de consumir su quantum (MLFQ)",
+		log.IntAttr("pid", proceso.PCB.PID),
+		log.IntAttr("nivel", proceso.PCB.NivelMLFQ),
+	)
+}
+
+// AlSerDesalojado baja un nivel al proceso que consumió todo su quantum. Si lo desalojó un proceso de menor nivel,
+// conserva el suyo.
+func (s *schedulerMLFQ) AlSerDesalojado(proceso *internal.Proceso, finDeQuantum bool) {
+	if !finDeQuantum {
+		return
+	}
+
+	if proceso.PCB.NivelMLFQ < s.cantidadNiveles()-1 {
+		proceso.PCB.NivelMLFQ++
+	}
+
+	s.service.Log.Debug("Proceso baja de nivel (MLFQ)",
+		log.IntAttr("pid", proceso.PCB.PID),
+		log.IntAttr("nivel", proceso.PCB.NivelMLFQ),
+	)
+}
+
+// DebeDesalojar devuelve el proceso en ejecución de mayor nivel, siempre que éste sea mayor al nivel del proceso
+// nuevo. Devuelve nil si no hay candidato.
+func (s *schedulerMLFQ) DebeDesalojar(procesoNuevo *internal.Proceso, exec []*internal.Proceso) *internal.Proceso {
+	var (
+		procesoADesalojar *internal.Proceso
+		nivelMax          = procesoNuevo.PCB.NivelMLFQ
+	)
+
+	for _, procesoEjecutando := range exec {
+		if procesoEjecutando == nil || procesoEjecutando.PCB == nil {
+			continue
+		}
+
+		if procesoEjecutando.PCB.NivelMLFQ > nivelMax {
+			procesoADesalojar = procesoEjecutando
+			nivelMax = procesoEjecutando.PCB.NivelMLFQ
+
+			s.service.Log.Debug("Candidato a desalojo encontrado (MLFQ)",
+				log.IntAttr("pid_candidato", procesoEjecutando.PCB.PID),
+				log.IntAttr("nivel_candidato", procesoEjecutando.PCB.NivelMLFQ),
+				log.IntAttr("nivel_nuevo", procesoNuevo.PCB.NivelMLFQ),
+			)
+		}
+	}
+
+	return procesoADesalojar
+}
+
+// Quantum devuelve el quantum del nivel en el que se encuentra el proceso y registra la ráfaga en las métricas de
+// ocupación por nivel.
+func (s *schedulerMLFQ) Quantum(proceso *internal.Proceso) int {
+	niveles := s.cantidadNiveles()
+	if niveles == 0 {
+		return 0
+	}
+
+	nivel := proceso.PCB.NivelMLFQ
+	if nivel >= niveles {
+		nivel = niveles - 1
+		proceso.PCB.NivelMLFQ = nivel
+	}
+
+	if proceso.PCB.RafagasPorNivel == nil {
+		proceso.PCB.RafagasPorNivel = make(map[int]int)
+	}
+	proceso.PCB.RafagasPorNivel[nivel]++
+
+	return s.service.MLFQConfig.Quantums[nivel]
+}
+
+// Metricas devuelve la ocupación por nivel del proceso (ráfagas ejecutadas en cada nivel) para agregar al log de
+// métricas de estado
+func (s *schedulerMLFQ) Metricas(proceso *internal.Proceso) string {
+	if s.cantidadNiveles() == 0 {
+		return ""
+	}
+
+	niveles := make([]string, 0, s.cantidadNiveles())
+	for nivel := range s.service.MLFQConfig.Quantums {
+		niveles = append(niveles, fmt.Sprintf("N%d %d", nivel, proceso.PCB.RafagasPorNivel[nivel]))
+	}
+
+	return ", MLFQ " + strings.Join(niveles, ", ")
+}
+
+func (s *schedulerMLFQ) cantidadNiveles() int {
+	if s.service.MLFQConfig == nil {
+		return 0
+	}
+
+	return len(s.service.MLFQConfig.Quantums)
+}
+
+// boost devuelve periódicamente a todos los procesos al nivel 0 para evitar la inanición de los procesos de los
+// niveles inferiores.
+func (s *schedulerMLFQ) boost() {
+	periodo := time.Duration(s.service.MLFQConfig.Boost) * time.Millisecond
+
+	for {
+		<-clock.After(periodo)
+		s.volverAlNivelCero()
+	}
+}
+
+// volverAlNivelCero pasa al nivel 0 a todos los procesos que no finalizaron
+func (s *schedulerMLFQ) volverAlNivelCero() {
+	p := s.service
+
+	colas := []struct {
+		mutex *sync.RWMutex
+		cola  *[]*internal.Proceso
+	}{
+		{p.mutexNewQueue, &p.Planificador.NewQueue},
+		{p.mutexReadyQueue, &p.Planificador.ReadyQueue},
+		{p.mutexExecQueue, &p.Planificador.ExecQueue},
+		{p.mutexBlockQueue, &p.Planificador.BlockQueue},
+		{p.mutexSuspReadyQueue, &p.Planificador.SuspReadyQueue},
+		{p.mutexSuspBlockQueue, &p.Planificador.SuspBlockQueue},
+	}
+
+	for _, c := range colas {
+		c.mutex.Lock()
+		for _, proc := range *c.cola {
+			if proc != nil && proc.PCB != nil {
+				proc.PCB.NivelMLFQ = 0
+			}
+		}
+		c.mutex.Unlock()
+	}
+
+	p.Log.Debug("Boost MLFQ: todos los procesos vuelven al nivel 0")
+}
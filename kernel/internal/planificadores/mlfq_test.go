@@ -0,0 +1,169 @@
+package planificadores
+
+import (
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+)
+
+func TestMLFQ_ElegirProximoYDesalojo(t *testing.T) {
+	tests := []struct {
+		nombre     string
+		niveles    []int // Nivel de cada proceso en READY, con PIDs 1, 2, 3...
+		elegido    int
+		orden      []int // PIDs de READY luego de elegir
+		exec       []int // Nivel de cada proceso en ejecución, con PIDs 11, 12, 13...
+		desalojado int   // PID a desalojar por el elegido, 0 si ninguno
+	}{
+		{
+			nombre:  "elige al de menor nivel",
+			niveles: []int{2, 0, 1},
+			elegido: 2,
+			orden:   []int{2, 3, 1},
+		},
+		{
+			nombre:  "en el mismo nivel respeta el orden de llegada",
+			niveles: []int{1, 0, 0, 1},
+			elegido: 2,
+			orden:   []int{2, 3, 1, 4},
+		},
+		{
+			nombre:     "desaloja al de mayor nivel en ejecución",
+			niveles:    []int{0},
+			elegido:    1,
+			orden:      []int{1},
+			exec:       []int{1, 2, 0},
+			desalojado: 12,
+		},
+		{
+			nombre:  "no desaloja a uno del mismo nivel",
+			niveles: []int{1},
+			elegido: 1,
+			orden:   []int{1},
+			exec:    []int{1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			s := &schedulerMLFQ{service: &Service{Log: slog.New(slog.NewTextHandler(io.Discard, nil))}}
+
+			var ready, exec []*internal.Proceso
+			for i, nivel := range tt.niveles {
+				ready = append(ready, &internal.Proceso{PCB: &internal.PCB{PID: i + 1, NivelMLFQ: nivel}})
+			}
+			for i, nivel := range tt.exec {
+				exec = append(exec, &internal.Proceso{PCB: &internal.PCB{PID: 11 + i, NivelMLFQ: nivel}})
+			}
+
+			elegido := s.ElegirProximo(ready)
+			if elegido.PCB.PID != tt.elegido {
+				t.Errorf("elegido = %d, se esperaba %d", elegido.PCB.PID, tt.elegido)
+			}
+
+			var orden []int
+			for _, proceso := range ready {
+				orden = append(orden, proceso.PCB.PID)
+			}
+			if !reflect.DeepEqual(orden, tt.orden) {
+				t.Errorf("READY = %v, se esperaba %v", orden, tt.orden)
+			}
+
+			desalojado := 0
+			if victima := s.DebeDesalojar(elegido, exec); victima != nil {
+				desalojado = victima.PCB.PID
+			}
+			if desalojado != tt.desalojado {
+				t.Errorf("desalojado = %d, se esperaba %d", desalojado, tt.desalojado)
+			}
+		})
+	}
+}
+
+func TestMLFQ_CambiosDeNivel(t *testing.T) {
+	tests := []struct {
+		nombre  string
+		nivel   int
+		evento  string // QUANTUM, DESALOJO o BLOQUEO
+		final   int
+		quantum int // Quantum de la próxima ráfaga
+	}{
+		{nombre: "baja al consumir su quantum", nivel: 0, evento: "QUANTUM", final: 1, quantum: 200},
+		{nombre: "no baja del último nivel", nivel: 2, evento: "QUANTUM", final: 2, quantum: 400},
+		{nombre: "conserva el nivel si lo desalojan", nivel: 1, evento: "DESALOJO", final: 1, quantum: 200},
+		{nombre: "sube al bloquearse", nivel: 2, evento: "BLOQUEO", final: 1, quantum: 200},
+		{nombre: "no sube del nivel 0", nivel: 0, evento: "BLOQUEO", final: 0, quantum: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			s := &schedulerMLFQ{service: &Service{
+				Log:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+				MLFQConfig: &MLFQConfig{Quantums: []int{100, 200, 400}},
+			}}
+			proceso := &internal.Proceso{PCB: &internal.PCB{PID: 1, NivelMLFQ: tt.nivel}}
+
+			switch tt.evento {
+			case "QUANTUM":
+				s.AlSerDesalojado(proceso, true)
+			case "DESALOJO":
+				s.AlSerDesalojado(proceso, false)
+			case "BLOQUEO":
+				s.AlBloquearse(proceso)
+			}
+
+			if proceso.PCB.NivelMLFQ != tt.final {
+				t.Errorf("nivel = %d, se esperaba %d", proceso.PCB.NivelMLFQ, tt.final)
+			}
+			if quantum := s.Quantum(proceso); quantum != tt.quantum {
+				t.Errorf("Quantum() = %d, se esperaba %d", quantum, tt.quantum)
+			}
+			if rafagas := proceso.PCB.RafagasPorNivel[tt.final]; rafagas != 1 {
+				t.Errorf("ráfagas en el nivel %d = %d, se esperaba 1", tt.final, rafagas)
+			}
+		})
+	}
+}
+
+func TestMLFQ_Boost(t *testing.T) {
+	proceso := func(pid, nivel int) *internal.Proceso {
+		return &internal.Proceso{PCB: &internal.PCB{PID: pid, NivelMLFQ: nivel}}
+	}
+
+	p := &Service{
+		Log: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Planificador: &Planificador{
+			NewQueue:       []*internal.Proceso{proceso(1, 2)},
+			ReadyQueue:     []*internal.Proceso{proceso(2, 1), proceso(3, 0)},
+			ExecQueue:      []*internal.Proceso{proceso(4, 2)},
+			BlockQueue:     []*internal.Proceso{proceso(5, 1)},
+			SuspReadyQueue: []*internal.Proceso{proceso(6, 2)},
+			SuspBlockQueue: []*internal.Proceso{proceso(7, 1)},
+		},
+		mutexNewQueue:       &sync.RWMutex{},
+		mutexReadyQueue:     &sync.RWMutex{},
+		mutexExecQueue:      &sync.RWMutex{},
+		mutexBlockQueue:     &sync.RWMutex{},
+		mutexSuspReadyQueue: &sync.RWMutex{},
+		mutexSuspBlockQueue: &sync.RWMutex{},
+	}
+	s := &schedulerMLFQ{service: p}
+
+	s.volverAlNivelCero()
+
+	colas := [][]*internal.Proceso{
+		p.Planificador.NewQueue, p.Planificador.ReadyQueue, p.Planificador.ExecQueue,
+		p.Planificador.BlockQueue, p.Planificador.SuspReadyQueue, p.Planificador.SuspBlockQueue,
+	}
+	for _, cola := range colas {
+		for _, enCola := range cola {
+			if enCola.PCB.NivelMLFQ != 0 {
+				t.Errorf("PID %d quedó en el nivel %d luego del boost", enCola.PCB.PID, enCola.PCB.NivelMLFQ)
+			}
+		}
+	}
+}
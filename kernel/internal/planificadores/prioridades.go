@@ -0,0 +1,101 @@
+package planificadores
+
+import (
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// schedulerPrioridades planifica los procesos por prioridad (a menor número, mayor prioridad). Entre los procesos de
+// la misma prioridad se respeta el orden de llegada a READY. Para evitar la inanición, los procesos que esperan en
+// READY más que el umbral de aging van ganando prioridad.
+// Con PRIORIDADES_DESALOJO, un proceso que llega a READY con mayor prioridad que alguno en ejecución lo desaloja.
+type schedulerPrioridades struct {
+	service  *Service
+	desalojo bool
+}
+
+// ElegirProximo aplica el aging a los procesos de ReadyQueue, los ordena por prioridad efectiva ascendente y
+// devuelve el primero
+func (s *schedulerPrioridades) ElegirProximo(ready []*internal.Proceso) *internal.Proceso {
+	for _, proc := range ready {
+		s.envejecer(proc)
+	}
+
+	ordenarEstable(ready, func(a, b *internal.Proceso) bool {
+		return a.PCB.PrioridadEfectiva < b.PCB.PrioridadEfectiva
+	})
+
+	return ready[0]
+}
+
+// AlIngresarReady restablece la prioridad original del proceso: el aging vuelve a contar desde que entra a READY
+func (s *schedulerPrioridades) AlIngresarReady(proceso *internal.Proceso) {
+	proceso.PCB.PrioridadEfectiva = proceso.PCB.Prioridad
+}
+
+func (s *schedulerPrioridades) AlBloquearse(*internal.Proceso) {}
+
+func (s *schedulerPrioridades) AlSerDesalojado(*internal.Proceso, bool) {}
+
+// DebeDesalojar devuelve el proceso en ejecución de menor prioridad, siempre que ésta sea menor a la prioridad
+// efectiva del proceso nuevo. Sin desalojo nunca devuelve un candidato.
+func (s *schedulerPrioridades) DebeDesalojar(procesoNuevo *internal.Proceso, exec []*internal.Proceso) *internal.Proceso {
+	if !s.desalojo {
+		return nil
+	}
+
+	var (
+		procesoADesalojar *internal.Proceso
+		prioridadMax      = procesoNuevo.PCB.PrioridadEfectiva
+	)
+
+	for _, procesoEjecutando := range exec {
+		if procesoEjecutando == nil || procesoEjecutando.PCB == nil {
+			continue
+		}
+
+		// El aging solo aplica en READY, en ejecución se compara con la prioridad original
+		if procesoEjecutando.PCB.Prioridad > prioridadMax {
+			procesoADesalojar = procesoEjecutando
+			prioridadMax = procesoEjecutando.PCB.Prioridad
+
+			s.service.Log.Debug("Candidato a desalojo encontrado (PRIORIDADES)",
+				log.IntAttr("pid_candidato", procesoEjecutando.PCB.PID),
+				log.IntAttr("prioridad_candidato", procesoEjecutando.PCB.Prioridad),
+				log.IntAttr("prioridad_nuevo", procesoNuevo.PCB.PrioridadEfectiva),
+			)
+		}
+	}
+
+	return procesoADesalojar
+}
+
+func (s *schedulerPrioridades) Quantum(*internal.Proceso) int {
+	return 0
+}
+
+// envejecer actualiza la prioridad efectiva de un proceso en READY: por cada umbral de aging que lleva esperando, su
+// prioridad mejora en uno, hasta llegar a 0.
+func (s *schedulerPrioridades) envejecer(proceso *internal.Proceso) {
+	config := s.service.PrioridadesConfig
+	tiempoReady := proceso.PCB.MetricasTiempo[internal.EstadoReady]
+	if config == nil || config.AgingThreshold <= 0 || tiempoReady == nil {
+		return
+	}
+
+	espera := clock.Since(tiempoReady.TiempoInicio).Milliseconds()
+	prioridad := proceso.PCB.Prioridad - int(espera/int64(config.AgingThreshold))
+	if prioridad < 0 {
+		prioridad = 0
+	}
+
+	if prioridad < proceso.PCB.PrioridadEfectiva {
+		s.service.Log.Debug("Aging: el proceso mejora su prioridad",
+			log.IntAttr("pid", proceso.PCB.PID),
+			log.IntAttr("prioridad_original", proceso.PCB.Prioridad),
+			log.IntAttr("prioridad_efectiva", prioridad),
+		)
+		proceso.PCB.PrioridadEfectiva = prioridad
+	}
+}
@@ -0,0 +1,139 @@
+package planificadores
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+)
+
+func TestPrioridades_Aging(t *testing.T) {
+	clock.Usar(clock.NewVirtual(time.Unix(0, 0)))
+	defer clock.Usar(clock.Real{})
+
+	type enReady struct {
+		prioridad int
+		espera    time.Duration // Tiempo que lleva en READY al elegir
+	}
+
+	tests := []struct {
+		nombre    string
+		umbral    int // aging_threshold en ms, 0 sin aging
+		ready     []enReady
+		elegido   int
+		efectivas []int // Prioridad efectiva de cada proceso luego de elegir
+	}{
+		{
+			nombre:    "elige al de mayor prioridad",
+			ready:     []enReady{{prioridad: 3}, {prioridad: 1}, {prioridad: 2}},
+			elegido:   2,
+			efectivas: []int{3, 1, 2},
+		},
+		{
+			nombre:    "a igual prioridad respeta el orden de llegada",
+			ready:     []enReady{{prioridad: 2}, {prioridad: 1}, {prioridad: 1}},
+			elegido:   2,
+			efectivas: []int{2, 1, 1},
+		},
+		{
+			nombre:    "sin umbral no hay aging",
+			ready:     []enReady{{prioridad: 3, espera: time.Second}, {prioridad: 1}},
+			elegido:   2,
+			efectivas: []int{3, 1},
+		},
+		{
+			nombre:    "gana una prioridad por cada umbral de espera",
+			umbral:    100,
+			ready:     []enReady{{prioridad: 3, espera: 250 * time.Millisecond}, {prioridad: 2}},
+			elegido:   1,
+			efectivas: []int{1, 2},
+		},
+		{
+			nombre:    "la prioridad efectiva no baja de 0",
+			umbral:    100,
+			ready:     []enReady{{prioridad: 1, espera: time.Second}, {prioridad: 0}},
+			elegido:   1,
+			efectivas: []int{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			s := &schedulerPrioridades{service: &Service{
+				Log:               slog.New(slog.NewTextHandler(io.Discard, nil)),
+				PrioridadesConfig: &PrioridadesConfig{AgingThreshold: tt.umbral},
+			}}
+
+			var ready, procesos []*internal.Proceso
+			for i, r := range tt.ready {
+				proceso := &internal.Proceso{PCB: &internal.PCB{
+					PID:       i + 1,
+					Prioridad: r.prioridad,
+					MetricasTiempo: map[internal.Estado]*internal.EstadoTiempo{
+						internal.EstadoReady: {TiempoInicio: clock.Now().Add(-r.espera)},
+					},
+				}}
+				s.AlIngresarReady(proceso)
+				ready = append(ready, proceso)
+				procesos = append(procesos, proceso)
+			}
+
+			if elegido := s.ElegirProximo(ready); elegido.PCB.PID != tt.elegido {
+				t.Errorf("elegido = %d, se esperaba %d", elegido.PCB.PID, tt.elegido)
+			}
+			for i, proceso := range procesos {
+				if proceso.PCB.PrioridadEfectiva != tt.efectivas[i] {
+					t.Errorf("prioridad efectiva de %d = %d, se esperaba %d",
+						proceso.PCB.PID, proceso.PCB.PrioridadEfectiva, tt.efectivas[i])
+				}
+			}
+
+			// Al volver a READY el aging cuenta de nuevo
+			s.AlIngresarReady(procesos[0])
+			if procesos[0].PCB.PrioridadEfectiva != procesos[0].PCB.Prioridad {
+				t.Errorf("prioridad efectiva al reingresar = %d, se esperaba %d",
+					procesos[0].PCB.PrioridadEfectiva, procesos[0].PCB.Prioridad)
+			}
+		})
+	}
+}
+
+func TestPrioridades_DebeDesalojar(t *testing.T) {
+	tests := []struct {
+		nombre     string
+		desalojo   bool
+		efectiva   int   // Prioridad efectiva del proceso que llega a READY
+		exec       []int // Prioridad de cada proceso en ejecución, con PIDs 11, 12, 13...
+		desalojado int   // 0 si no se desaloja a ninguno
+	}{
+		{nombre: "sin desalojo nunca desaloja", efectiva: 0, exec: []int{3}},
+		{nombre: "desaloja al de menor prioridad", desalojo: true, efectiva: 1, exec: []int{2, 4, 3}, desalojado: 12},
+		{nombre: "no desaloja a uno de igual prioridad", desalojo: true, efectiva: 2, exec: []int{2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			s := &schedulerPrioridades{
+				service:  &Service{Log: slog.New(slog.NewTextHandler(io.Discard, nil))},
+				desalojo: tt.desalojo,
+			}
+
+			nuevo := &internal.Proceso{PCB: &internal.PCB{PID: 1, PrioridadEfectiva: tt.efectiva}}
+			var exec []*internal.Proceso
+			for i, prioridad := range tt.exec {
+				exec = append(exec, &internal.Proceso{PCB: &internal.PCB{PID: 11 + i, Prioridad: prioridad}})
+			}
+
+			desalojado := 0
+			if victima := s.DebeDesalojar(nuevo, exec); victima != nil {
+				desalojado = victima.PCB.PID
+			}
+			if desalojado != tt.desalojado {
+				t.Errorf("desalojado = %d, se esperaba %d", desalojado, tt.desalojado)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package planificadores
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+const (
+	TicketsPorDefecto = 100     // Tickets de un proceso cuando no se indican ni en INIT_PROC ni en la configuración
+	strideBase        = 1 << 20 // Constante que se divide por los tickets para obtener el stride de cada proceso
+)
+
+// schedulerProporcional reparte la CPU en proporción a los tickets de cada proceso:
+// * LOTTERY sortea en cada despacho un ticket entre todos los procesos en READY; cada proceso tiene tantas chances
+// como tickets.
+// * STRIDE elige de forma determinística al proceso de menor pase. Al dejar la CPU, el pase del proceso avanza
+// proporcionalmente al tiempo que ejecutó dividido sus tickets.
+// Ambos desalojan por fin de quantum (el mismo de RR) para que la CPU se vuelva a repartir periódicamente.
+type schedulerProporcional struct {
+	service *Service
+	stride  bool
+
+	mutex      sync.Mutex
+	rng        *rand.Rand
+	sorteo     float64               // Número sorteado para el próximo despacho (LOTTERY), en [0, 1)
+	paseGlobal float64               // Pase del último proceso elegido (STRIDE), se asigna a los procesos nuevos
+	tickets    map[int]int           // Tickets de cada proceso vivo que pasó por el planificador
+	consumido  map[int]time.Duration // Tiempo de CPU consumido por cada proceso vivo que pasó por el planificador
+}
+
+func nuevoSchedulerProporcional(p *Service, stride bool) *schedulerProporcional {
+	semilla := clock.Now().UnixNano()
+	if p.ProporcionalConfig != nil && p.ProporcionalConfig.Semilla != 0 {
+		semilla = p.ProporcionalConfig.Semilla
+	}
+
+	s := &schedulerProporcional{
+		service:   p,
+		stride:    stride,
+		rng:       rand.New(rand.NewSource(semilla)),
+		tickets:   make(map[int]int),
+		consumido: make(map[int]time.Duration),
+	}
+	s.sorteo = s.rng.Float64()
+
+	p.Log.Debug("Planificador proporcional inicializado",
+		log.AnyAttr("stride", stride),
+		log.AnyAttr("semilla", semilla),
+	)
+
+	return s
+}
+
+// ElegirProximo sortea un ticket (LOTTERY) o elige al proceso de menor pase (STRIDE). El número sorteado se mantiene
+// hasta que el proceso efectivamente pase a ejecutar, así la secuencia de sorteos es reproducible con la misma
+// semilla.
+func (s *schedulerProporcional) ElegirProximo(ready []*internal.Proceso) *internal.Proceso {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stride {
+		elegido := ready[0]
+		for _, proc := range ready[1:] {
+			if proc.PCB.Pase < elegido.PCB.Pase {
+				elegido = proc
+			}
+		}
+		return elegido
+	}
+
+	var total int
+	for _, proc := range ready {
+		total += ticketsDe(proc)
+	}
+
+	ganador := int(s.sorteo * float64(total))
+	for _, proc := range ready {
+		ganador -= ticketsDe(proc)
+		if ganador < 0 {
+			return proc
+		}
+	}
+
+	return ready[len(ready)-1]
+}
+
+// AlIngresarReady registra los tickets del proceso la primera vez que llega a READY. Con STRIDE, el proceso nuevo
+// parte del pase global para no monopolizar la CPU hasta alcanzar a los demás.
+func (s *schedulerProporcional) AlIngresarReady(proceso *internal.Proceso) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.tickets[proceso.PCB.PID]; ok {
+		return
+	}
+
+	s.tickets[proceso.PCB.PID] = ticketsDe(proceso)
+	s.consumido[proceso.PCB.PID] = 0
+	if s.stride {
+		proceso.PCB.Pase = s.paseGlobal
+	}
+}
+
+func (s *schedulerProporcional) AlBloquearse(proceso *internal.Proceso) {
+	s.registrarConsumo(proceso)
+}
+
+func (s *schedulerProporcional) AlSerDesalojado(proceso *internal.Proceso, _ bool) {
+	s.registrarConsumo(proceso)
+}
+
+func (s *schedulerProporcional) DebeDesalojar(*internal.Proceso, []*internal.Proceso) *internal.Proceso {
+	return nil
+}
+
+// Quantum confirma el despacho del proceso elegido: se sortea el número del próximo despacho (LOTTERY) y se avanza
+// el pase global (STRIDE)
+func (s *schedulerProporcional) Quantum(proceso *internal.Proceso) int {
+	s.mutex.Lock()
+	if s.stride {
+		if proceso.PCB.Pase > s.paseGlobal {
+			s.paseGlobal = proceso.PCB.Pase
+		}
+	} else {
+		s.sorteo = s.rng.Float64()
+	}
+	s.mutex.Unlock()
+
+	if s.service.RoundRobinConfig == nil {
+		return 0
+	}
+
+	return s.service.RoundRobinConfig.Quantum
+}
+
+// Metricas devuelve la cuota de CPU obtenida por el proceso contra la que le corresponde por sus tickets, ambas
+// respecto de los procesos que todavía no finalizaron
+func (s *schedulerProporcional) Metricas(proceso *internal.Proceso) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Al finalizar, el tiempo en EXEC del PCB ya incluye la última ráfaga
+	if tiempoExec := proceso.PCB.MetricasTiempo[internal.EstadoExec]; tiempoExec != nil {
+		s.consumido[proceso.PCB.PID] = tiempoExec.TiempoAcumulado
+	}
+
+	var (
+		ticketsTotales int
+		consumoTotal   time.Duration
+	)
+	for pid, tickets := range s.tickets {
+		ticketsTotales += tickets
+		consumoTotal += s.consumido[pid]
+	}
+
+	var asignada, obtenida float64
+	if ticketsTotales > 0 {
+		asignada = float64(ticketsDe(proceso)) / float64(ticketsTotales) * 100
+	}
+	if consumoTotal > 0 {
+		obtenida = float64(s.consumido[proceso.PCB.PID]) / float64(consumoTotal) * 100
+	}
+
+	return fmt.Sprintf(", TICKETS %d, CUOTA OBTENIDA %.2f%% ASIGNADA %.2f%%", ticketsDe(proceso), obtenida, asignada)
+}
+
+// AlFinalizar saca al proceso de los tickets y del consumo, para que no cuente en las cuotas de los que siguen
+func (s *schedulerProporcional) AlFinalizar(proceso *internal.Proceso) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.tickets, proceso.PCB.PID)
+	delete(s.consumido, proceso.PCB.PID)
+}
+
+// registrarConsumo suma el tiempo que el proceso ejecutó en la ráfaga que termina y, con STRIDE, avanza su pase
+func (s *schedulerProporcional) registrarConsumo(proceso *internal.Proceso) {
+	tiempoExec := proceso.PCB.MetricasTiempo[internal.EstadoExec]
+	if tiempoExec == nil {
+		return
+	}
+	rafaga := clock.Since(tiempoExec.TiempoInicio)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.consumido[proceso.PCB.PID] += rafaga
+	if s.stride {
+		proceso.PCB.Pase += float64(rafaga.Milliseconds()) * strideBase / float64(ticketsDe(proceso))
+
+		s.service.Log.Debug("Pase actualizado (STRIDE)",
+			log.IntAttr("pid", proceso.PCB.PID),
+			log.AnyAttr("pase", proceso.PCB.Pase),
+		)
+	}
+}
+
+// ticketsDe devuelve los tickets del proceso; un proceso siempre tiene al menos un ticket
+func ticketsDe(proceso *internal.Proceso) int {
+	if proceso.PCB.Tickets <= 0 {
+		return 1
+	}
+
+	return proceso.PCB.Tickets
+}
@@ -0,0 +1,142 @@
+package planificadores
+
+import (
+	"io"
+	"log/slog"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+)
+
+func TestProporcional_LotteryReproducible(t *testing.T) {
+	// sortear devuelve los PIDs elegidos en despachos sucesivos, con procesos de 10, 30 y 60 tickets
+	sortear := func(semilla int64, despachos int) []int {
+		s := nuevoSchedulerProporcional(&Service{
+			Log:                slog.New(slog.NewTextHandler(io.Discard, nil)),
+			ProporcionalConfig: &ProporcionalConfig{Semilla: semilla},
+		}, false)
+
+		ready := []*internal.Proceso{
+			{PCB: &internal.PCB{PID: 1, Tickets: 10}},
+			{PCB: &internal.PCB{PID: 2, Tickets: 30}},
+			{PCB: &internal.PCB{PID: 3, Tickets: 60}},
+		}
+
+		var elegidos []int
+		for i := 0; i < despachos; i++ {
+			elegido := s.ElegirProximo(ready)
+			// Hasta que el elegido pasa a ejecutar, el sorteo no cambia
+			if otro := s.ElegirProximo(ready); otro != elegido {
+				t.Fatalf("despacho %d: se eligió %d y luego %d sin despachar", i, elegido.PCB.PID, otro.PCB.PID)
+			}
+			s.Quantum(elegido)
+			elegidos = append(elegidos, elegido.PCB.PID)
+		}
+		return elegidos
+	}
+
+	primera, segunda := sortear(42, 1000), sortear(42, 1000)
+	if !reflect.DeepEqual(primera, segunda) {
+		t.Fatalf("con la misma semilla se obtuvieron secuencias distintas")
+	}
+	if reflect.DeepEqual(primera, sortear(7, 1000)) {
+		t.Errorf("con semillas distintas se obtuvo la misma secuencia")
+	}
+
+	// Cada proceso gana en proporción a sus tickets
+	veces := make(map[int]int)
+	for _, pid := range primera {
+		veces[pid]++
+	}
+	esperadas := map[int]int{1: 100, 2: 300, 3: 600}
+	for pid, esperada := range esperadas {
+		if veces[pid] < esperada*8/10 || veces[pid] > esperada*12/10 {
+			t.Errorf("PID %d ganó %d de 1000 sorteos, se esperaban cerca de %d", pid, veces[pid], esperada)
+		}
+	}
+}
+
+func TestProporcional_Stride(t *testing.T) {
+	reloj := clock.NewVirtual(time.Unix(0, 0))
+	clock.Usar(reloj)
+	defer clock.Usar(clock.Real{})
+
+	tests := []struct {
+		nombre  string
+		tickets []int         // Tickets de cada proceso, con PIDs 1, 2, 3...
+		rafaga  time.Duration // Lo que ejecuta cada proceso elegido antes de dejar la CPU
+		elegido []int         // PIDs elegidos en despachos sucesivos
+		pases   []float64     // Pase final de cada proceso
+	}{
+		{
+			nombre:  "a igual pase se elige por orden de llegada",
+			tickets: []int{100, 100},
+			rafaga:  10 * time.Millisecond,
+			elegido: []int{1, 2, 1, 2},
+			pases:   []float64{2 * 10.0 * strideBase / 100, 2 * 10.0 * strideBase / 100},
+		},
+		{
+			nombre:  "el pase avanza en proporción inversa a los tickets",
+			tickets: []int{200, 100},
+			rafaga:  10 * time.Millisecond,
+			elegido: []int{1, 2, 1, 1, 2, 1},
+			pases:   []float64{4 * 10.0 * strideBase / 200, 2 * 10.0 * strideBase / 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			s := nuevoSchedulerProporcional(&Service{Log: slog.New(slog.NewTextHandler(io.Discard, nil))}, true)
+
+			var ready []*internal.Proceso
+			for i, tickets := range tt.tickets {
+				proceso := &internal.Proceso{PCB: &internal.PCB{
+					PID:            i + 1,
+					Tickets:        tickets,
+					MetricasTiempo: map[internal.Estado]*internal.EstadoTiempo{internal.EstadoExec: {}},
+				}}
+				s.AlIngresarReady(proceso)
+				ready = append(ready, proceso)
+			}
+
+			var elegidos []int
+			for range tt.elegido {
+				elegido := s.ElegirProximo(ready)
+				s.Quantum(elegido)
+				elegido.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio = clock.Now()
+				reloj.Sleep(tt.rafaga)
+				s.AlSerDesalojado(elegido, true)
+				elegidos = append(elegidos, elegido.PCB.PID)
+			}
+
+			if !reflect.DeepEqual(elegidos, tt.elegido) {
+				t.Errorf("elegidos = %v, se esperaba %v", elegidos, tt.elegido)
+			}
+			for i, proceso := range ready {
+				if math.Abs(proceso.PCB.Pase-tt.pases[i]) > 1e-6 {
+					t.Errorf("pase de %d = %v, se esperaba %v", proceso.PCB.PID, proceso.PCB.Pase, tt.pases[i])
+				}
+			}
+
+			// Un proceso nuevo parte del pase del último despachado, no de 0
+			nuevo := &internal.Proceso{PCB: &internal.PCB{PID: 9, Tickets: 100}}
+			s.AlIngresarReady(nuevo)
+			if nuevo.PCB.Pase != s.paseGlobal || nuevo.PCB.Pase == 0 {
+				t.Errorf("pase del proceso nuevo = %v, se esperaba el pase global %v", nuevo.PCB.Pase, s.paseGlobal)
+			}
+
+			// Al finalizar deja de contar en las cuotas
+			s.AlFinalizar(ready[0])
+			if _, ok := s.tickets[1]; ok {
+				t.Errorf("los tickets del proceso finalizado siguen registrados")
+			}
+			if _, ok := s.consumido[1]; ok {
+				t.Errorf("el consumo del proceso finalizado sigue registrado")
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package planificadores
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+var ErrRecursoInexistente = errors.New("recurso inexistente")
+
+// recurso es un semáforo del kernel, definido en la configuración (resources)
+type recurso struct {
+	nombre     string
+	instancias int                 // Instancias libres
+	esperando  []*internal.Proceso // Procesos bloqueados esperando una instancia, en orden de llegada
+}
+
+// ConfigurarRecursos crea los recursos del kernel con sus instancias iniciales. Los procesos los usan con las
+// syscalls WAIT <recurso> y SIGNAL <recurso>. Los nombres numéricos y ANY se descartan, porque WAIT con esos
+// argumentos espera a un hijo.
+func (p *Service) ConfigurarRecursos(instancias map[string]int) {
+	p.mutexRecursos.Lock()
+	defer p.mutexRecursos.Unlock()
+
+	for nombre, cantidad := range instancias {
+		if esNombreDeHijo(nombre) {
+			p.Log.Warn("Recurso con nombre reservado para WAIT de un hijo, se descarta",
+				log.StringAttr("recurso", nombre),
+			)
+			continue
+		}
+		if cantidad < 0 {
+			p.Log.Warn("Recurso con instancias negativas, se inicia sin instancias",
+				log.StringAttr("recurso", nombre),
+				log.IntAttr("instancias", cantidad),
+			)
+			cantidad = 0
+		}
+		p.recursos[nombre] = &recurso{nombre: nombre, instancias: cantidad}
+	}
+}
+
+// esNombreDeHijo indica si WAIT con ese argumento espera a un hijo (su PID o ANY) en lugar de tomar un recurso
+func esNombreDeHijo(nombre string) bool {
+	if strings.EqualFold(nombre, "ANY") {
+		return true
+	}
+	_, err := strconv.Atoi(nombre)
+	return err == nil
+}
+
+// ExisteRecurso informa si hay un recurso configurado con el nombre dado
+func (p *Service) ExisteRecurso(nombre string) bool {
+	p.mutexRecursos.Lock()
+	defer p.mutexRecursos.Unlock()
+
+	_, ok := p.recursos[nombre]
+	return ok
+}
+
+// TomarRecurso atiende WAIT mientras el proceso sigue ejecutando: si hay una instancia libre se la asigna y devuelve
+// true. Si no hay, no bloquea al proceso: devuelve false y la CPU devuelve el contexto para que se lo bloquee con
+// EsperarRecurso.
+func (p *Service) TomarRecurso(pid int, nombre string) (bool, error) {
+	p.mutexRecursos.Lock()
+	defer p.mutexRecursos.Unlock()
+
+	r, ok := p.recursos[nombre]
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrRecursoInexistente, nombre)
+	}
+	if r.instancias == 0 {
+		return false, nil
+	}
+
+	p.asignarInstancia(pid, r)
+	p.Log.Info(fmt.Sprintf("## (%d) - WAIT: Toma una instancia de %s - Disponibles: %d", pid, nombre, r.instancias))
+	return true, nil
+}
+
+// EsperarRecurso atiende WAIT cuando el proceso ya devolvió la CPU: si no hay instancias libres lo bloquea en la
+// cola del recurso hasta que otro proceso haga SIGNAL. Si mientras tanto se liberó una, la toma y se desbloquea en el
+// momento.
+func (p *Service) EsperarRecurso(pid int, nombre string) error {
+	// Los recursos quedan bloqueados hasta que el proceso esté en BLOCKED, para que ningún SIGNAL lo despierte antes
+	p.mutexRecursos.Lock()
+	r, ok := p.recursos[nombre]
+	if !ok {
+		p.mutexRecursos.Unlock()
+		return fmt.Errorf("%w: %s", ErrRecursoInexistente, nombre)
+	}
+
+	proceso, err := p.bloquear(pid, nombre, nil)
+	if err != nil {
+		p.mutexRecursos.Unlock()
+		return err
+	}
+
+	if r.instancias > 0 {
+		p.asignarInstancia(pid, r)
+		disponibles := r.instancias
+		p.mutexRecursos.Unlock()
+
+		p.Log.Info(fmt.Sprintf("## (%d) - WAIT: Toma una instancia de %s - Disponibles: %d", pid, nombre, disponibles))
+		p.ManejarFinIO(proceso)
+		return nil
+	}
+
+	r.esperando = append(r.esperando, proceso)
+	p.mutexRecursos.Unlock()
+
+	p.Log.Info(fmt.Sprintf("## (%d) - Bloqueado por recurso: %s", pid, nombre))
+	p.alBloquearsePorRecurso()
+	return nil
+}
+
+// LiberarRecurso atiende SIGNAL: devuelve una instancia del recurso y, si hay procesos esperándolo, se la asigna al
+// primero y lo desbloquea
+func (p *Service) LiberarRecurso(pid int, nombre string) error {
+	p.mutexRecursos.Lock()
+	r, ok := p.recursos[nombre]
+	if !ok {
+		p.mutexRecursos.Unlock()
+		return fmt.Errorf("%w: %s", ErrRecursoInexistente, nombre)
+	}
+
+	if asignadas := p.asignaciones[pid]; asignadas[nombre] > 0 {
+		asignadas[nombre]--
+		if asignadas[nombre] == 0 {
+			delete(asignadas, nombre)
+		}
+	}
+	despertado := p.devolverInstancia(r)
+	disponibles := r.instancias
+	p.mutexRecursos.Unlock()
+
+	p.Log.Info(fmt.Sprintf("## (%d) - SIGNAL: Libera una instancia de %s - Disponibles: %d", pid, nombre, disponibles))
+	p.despertarPorRecurso(despertado, nombre)
+
+	return nil
+}
+
+// liberarRecursos se llama cuando un proceso finaliza: lo saca de las colas de espera y libera las instancias que
+// todavía tenía asignadas
+func (p *Service) liberarRecursos(pid int) {
+	p.mutexRecursos.Lock()
+	for _, r := range p.recursos {
+		r.esperando, _ = p.removerDeCola(pid, r.esperando)
+	}
+
+	asignadas := p.asignaciones[pid]
+	delete(p.asignaciones, pid)
+
+	nombres := make([]string, 0, len(asignadas))
+	for nombre := range asignadas {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+
+	despertados := make(map[string][]*internal.Proceso)
+	for _, nombre := range nombres {
+		r := p.recursos[nombre]
+		for i := 0; i < asignadas[nombre]; i++ {
+			if despertado := p.devolverInstancia(r); despertado != nil {
+				despertados[nombre] = append(despertados[nombre], despertado)
+			}
+		}
+	}
+	p.mutexRecursos.Unlock()
+
+	for _, nombre := range nombres {
+		p.Log.Info(fmt.Sprintf("## (%d) - Finaliza con %d instancias de %s, se liberan", pid, asignadas[nombre], nombre))
+		for _, despertado := range despertados[nombre] {
+			p.despertarPorRecurso(despertado, nombre)
+		}
+	}
+}
+
+// asignarInstancia le da una instancia libre del recurso al proceso
+// IMPORTANTE: Se llama con mutexRecursos bloqueado y al menos una instancia libre
+func (p *Service) asignarInstancia(pid int, r *recurso) {
+	r.instancias--
+	if p.asignaciones[pid] == nil {
+		p.asignaciones[pid] = make(map[string]int)
+	}
+	p.asignaciones[pid][r.nombre]++
+}
+
+// devolverInstancia suma una instancia al recurso y, si alguien la espera, se la asigna al primero. Devuelve el
+// proceso al que se le asignó, que hay que desbloquear, o nil.
+// IMPORTANTE: Se llama con mutexRecursos bloqueado
+func (p *Service) devolverInstancia(r *recurso) *internal.Proceso {
+	r.instancias++
+
+	for len(r.esperando) > 0 {
+		proceso := r.esperando[0]
+		r.esperando = r.esperando[1:]
+		if proceso == nil || proceso.PCB == nil {
+			continue
+		}
+
+		p.asignarInstancia(proceso.PCB.PID, r)
+		return proceso
+	}
+
+	return nil
+}
+
+// despertarPorRecurso desbloquea al proceso al que se le asignó una instancia del recurso
+func (p *Service) despertarPorRecurso(proceso *internal.Proceso, nombre string) {
+	if proceso == nil {
+		return
+	}
+
+	p.Log.Info(fmt.Sprintf("## (%d) - Obtiene una instancia de %s, se desbloquea", proceso.PCB.PID, nombre))
+	p.ManejarFinIO(proceso)
+}
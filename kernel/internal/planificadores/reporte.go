@@ -0,0 +1,268 @@
+package planificadores
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/kernel/pkg/memoria"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// MotivoFinalizacionNormal es el motivo de los procesos que no finalizaron por un error
+const MotivoFinalizacionNormal = "EXIT"
+
+// estadosReporte son los estados, en orden, de las columnas del reporte CSV
+var estadosReporte = []internal.Estado{
+	internal.EstadoNew,
+	internal.EstadoReady,
+	internal.EstadoExec,
+	internal.EstadoBloqueado,
+	internal.EstadoSuspBloqueado,
+	internal.EstadoSuspReady,
+	internal.EstadoExit,
+}
+
+// MetricaEstado es la cantidad de veces que un proceso pasó por un estado y el tiempo total que estuvo en él
+type MetricaEstado struct {
+	Cantidad int   `json:"cantidad"`
+	TiempoMs int64 `json:"tiempo_ms"`
+}
+
+// RegistroFinalizacion es la línea del reporte de un proceso finalizado
+type RegistroFinalizacion struct {
+	PID             int                               `json:"pid"`
+	PIDPadre        int                               `json:"pid_padre"` // 0 para el proceso inicial y los huérfanos sin abuelo
+	PIDRaiz         int                               `json:"pid_raiz"`  // Primer proceso del árbol, identifica la corrida
+	Archivo         string                            `json:"archivo"`
+	Motivo          string                            `json:"motivo"`
+	Estados         map[internal.Estado]MetricaEstado `json:"estados"`
+	Rafagas         int                               `json:"rafagas"`           // Ráfagas de CPU, una por cada paso a EXEC
+	EstimacionSJF   float64                           `json:"estimacion_sjf"`    // Última estimación de ráfaga en ms (SJF/SRT)
+	UltimaRafagaMs  int64                             `json:"ultima_rafaga_ms"`  // Duración real de la última ráfaga
+	DispositivosIO  map[string]int                    `json:"dispositivos_io"`   // Veces que usó cada dispositivo IO
+	MetricasMemoria *memoria.MetricasProceso          `json:"memoria,omitempty"` // Métricas de memoria, si se pudieron consultar
+}
+
+// Reporte escribe un registro por cada proceso finalizado en los archivos procesos-<inicio>.jsonl y
+// procesos-<inicio>.csv del directorio configurado, uno por ejecución del kernel
+type Reporte struct {
+	mutex      sync.Mutex
+	rutaJSONL  string
+	rutaCSV    string
+	cabeceraOK bool // Si ya se escribió la cabecera del CSV
+}
+
+// NewReporte crea el reporte de esta ejecución en el directorio dado
+func NewReporte(directorio string) (*Reporte, error) {
+	if err := os.MkdirAll(directorio, 0755); err != nil {
+		return nil, fmt.Errorf("error creando directorio del reporte: %w", err)
+	}
+
+	base := filepath.Join(directorio, "procesos-"+time.Now().Format("20060102-150405"))
+	return &Reporte{
+		rutaJSONL: base + ".jsonl",
+		rutaCSV:   base + ".csv",
+	}, nil
+}
+
+// NuevoRegistroFinalizacion arma el registro del reporte a partir del PCB de un proceso que pasó a EXIT
+func NuevoRegistroFinalizacion(pcb *internal.PCB) RegistroFinalizacion {
+	registro := RegistroFinalizacion{
+		PID:            pcb.PID,
+		PIDPadre:       pcb.PIDPadre,
+		PIDRaiz:        pcb.PIDRaiz,
+		Archivo:        pcb.NombreArchivo,
+		Motivo:         pcb.MotivoFinalizacion,
+		Estados:        make(map[internal.Estado]MetricaEstado, len(estadosReporte)),
+		Rafagas:        pcb.MetricasEstado[internal.EstadoExec],
+		EstimacionSJF:  pcb.EstimacionAnterior,
+		DispositivosIO: make(map[string]int, len(pcb.DispositivosIO)),
+	}
+
+	if registro.Motivo == "" {
+		registro.Motivo = MotivoFinalizacionNormal
+	}
+
+	for _, estado := range estadosReporte {
+		metrica := MetricaEstado{Cantidad: pcb.MetricasEstado[estado]}
+		if tiempo := pcb.MetricasTiempo[estado]; tiempo != nil {
+			metrica.TiempoMs = tiempo.TiempoAcumulado.Milliseconds()
+		}
+		registro.Estados[estado] = metrica
+	}
+
+	if pcb.RafagaAnterior != nil {
+		registro.UltimaRafagaMs = pcb.RafagaAnterior.Milliseconds()
+	}
+
+	for dispositivo, veces := range pcb.DispositivosIO {
+		registro.DispositivosIO[dispositivo] = veces
+	}
+
+	return registro
+}
+
+// Registrar agrega el registro al final de los archivos JSON lines y CSV
+func (r *Reporte) Registrar(registro RegistroFinalizacion) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	linea, err := json.Marshal(registro)
+	if err != nil {
+		return fmt.Errorf("error serializando el registro del proceso %d: %w", registro.PID, err)
+	}
+
+	if err = agregarAArchivo(r.rutaJSONL, func(f *os.File) error {
+		_, err := f.Write(append(linea, '\n'))
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return agregarAArchivo(r.rutaCSV, func(f *os.File) error {
+		w := csv.NewWriter(f)
+		if !r.cabeceraOK {
+			if err := w.Write(cabeceraCSV()); err != nil {
+				return err
+			}
+		}
+		if err := w.Write(filaCSV(registro)); err != nil {
+			return err
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+
+		r.cabeceraOK = true
+		return nil
+	})
+}
+
+// agregarAArchivo abre el archivo para agregar al final, creándolo si no existe, y ejecuta escribir sobre él
+func agregarAArchivo(ruta string, escribir func(f *os.File) error) error {
+	f, err := os.OpenFile(ruta, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error abriendo %s: %w", ruta, err)
+	}
+
+	if err = escribir(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error escribiendo %s: %w", ruta, err)
+	}
+
+	return f.Close()
+}
+
+func cabeceraCSV() []string {
+	cabecera := []string{"pid", "pid_padre", "pid_raiz", "archivo", "motivo"}
+	for _, estado := range estadosReporte {
+		nombre := strings.ToLower(strings.ReplaceAll(string(estado), ".", "_"))
+		cabecera = append(cabecera, nombre+"_cantidad", nombre+"_ms")
+	}
+
+	return append(cabecera, "rafagas", "estimacion_sjf", "ultima_rafaga_ms", "dispositivos_io",
+		"accesos_tabla_de_paginas", "instrucciones_solicitadas", "bajadas_a_swap", "subidas_a_memoria_principal",
+		"lecturas_de_memoria", "escrituras_de_memoria")
+}
+
+// filaCSV devuelve el registro como fila del CSV. Los dispositivos IO van en una sola columna como
+// "DISPOSITIVO:VECES" separados por ";", y las métricas de memoria quedan vacías si no se pudieron consultar.
+func filaCSV(registro RegistroFinalizacion) []string {
+	fila := []string{
+		strconv.Itoa(registro.PID),
+		strconv.Itoa(registro.PIDPadre),
+		strconv.Itoa(registro.PIDRaiz),
+		registro.Archivo,
+		registro.Motivo,
+	}
+	for _, estado := range estadosReporte {
+		metrica := registro.Estados[estado]
+		fila = append(fila, strconv.Itoa(metrica.Cantidad), strconv.FormatInt(metrica.TiempoMs, 10))
+	}
+
+	dispositivos := make([]string, 0, len(registro.DispositivosIO))
+	for dispositivo, veces := range registro.DispositivosIO {
+		dispositivos = append(dispositivos, fmt.Sprintf("%s:%d", dispositivo, veces))
+	}
+	sort.Strings(dispositivos)
+
+	fila = append(fila,
+		strconv.Itoa(registro.Rafagas),
+		strconv.FormatFloat(registro.EstimacionSJF, 'f', -1, 64),
+		strconv.FormatInt(registro.UltimaRafagaMs, 10),
+		strings.Join(dispositivos, ";"),
+	)
+
+	if m := registro.MetricasMemoria; m != nil {
+		return append(fila,
+			strconv.Itoa(m.AccesosTablaDePaginas), strconv.Itoa(m.InstruccionesSolicitadas),
+			strconv.Itoa(m.BajadasAlSwap), strconv.Itoa(m.SubidasAMemPpal),
+			strconv.Itoa(m.LecturasDeMemoria), strconv.Itoa(m.EscriturasDeMemoria),
+		)
+	}
+
+	return append(fila, "", "", "", "", "", "")
+}
+
+// registrarFinalizacion escribe el log obligatorio de métricas de estado del proceso que pasó a EXIT y, si el
+// reporte está habilitado, lo agrega junto con las métricas que memoria registró hasta destruirlo.
+// IMPORTANTE: Se llama antes de liberar el PCB, y después de que memoria haya finalizado el proceso
+func (p *Service) registrarFinalizacion(proceso *internal.Proceso) {
+	pcb := proceso.PCB
+
+	// Log obligatorio: Métricas de Estado
+	//"## (<PID>) - Métricas de estado: NEW (NEW_COUNT) (NEW_TIME), READY (READY_COUNT) (READY_TIME), …"
+	p.Log.Info(fmt.Sprintf("## (%d) - Métricas de estado: NEW %d %d, READY %d %d, "+
+		"EXEC %d %d, BLOCKED %d %d, SUSP. BLOCKED %d %d, SUSP. READY %d %d, EXIT %d %d%s",
+		pcb.PID,
+		pcb.MetricasEstado[internal.EstadoNew],
+		pcb.MetricasTiempo[internal.EstadoNew].TiempoAcumulado.Milliseconds(),
+		pcb.MetricasEstado[internal.EstadoReady],
+		pcb.MetricasTiempo[internal.EstadoReady].TiempoAcumulado.Milliseconds(),
+		pcb.MetricasEstado[internal.EstadoExec],
+		pcb.MetricasTiempo[internal.EstadoExec].TiempoAcumulado.Milliseconds(),
+		pcb.MetricasEstado[internal.EstadoBloqueado],
+		pcb.MetricasTiempo[internal.EstadoBloqueado].TiempoAcumulado.Milliseconds(),
+		pcb.MetricasEstado[internal.EstadoSuspBloqueado],
+		pcb.MetricasTiempo[internal.EstadoSuspBloqueado].TiempoAcumulado.Milliseconds(),
+		pcb.MetricasEstado[internal.EstadoSuspReady],
+		pcb.MetricasTiempo[internal.EstadoSuspReady].TiempoAcumulado.Milliseconds(),
+		pcb.MetricasEstado[internal.EstadoExit],
+		pcb.MetricasTiempo[internal.EstadoExit].TiempoAcumulado.Milliseconds(),
+		p.metricasAdicionales(proceso),
+	),
+	)
+
+	if p.Reporte == nil {
+		return
+	}
+
+	registro := NuevoRegistroFinalizacion(pcb)
+
+	metricasMemoria, err := p.Memoria.MetricasProceso(pcb.PID)
+	if err != nil {
+		p.Log.Warn("No se pudieron obtener las métricas de memoria del proceso, se reporta sin ellas",
+			log.ErrAttr(err),
+			log.IntAttr("pid", pcb.PID),
+		)
+	}
+	registro.MetricasMemoria = metricasMemoria
+
+	if err = p.Reporte.Registrar(registro); err != nil {
+		p.Log.Error("Error al escribir el reporte de finalización",
+			log.ErrAttr(err),
+			log.IntAttr("pid", pcb.PID),
+			log.StringAttr("archivo", p.Reporte.rutaJSONL),
+		)
+	}
+}
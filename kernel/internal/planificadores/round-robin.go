@@ -0,0 +1,180 @@
+package planificadores
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/kernel/pkg/cpu"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// schedulerRoundRobin ejecuta los procesos en orden de llegada a READY, asignándoles la CPU por un quantum como
+// máximo. Al vencer el quantum se desaloja al proceso y se lo devuelve al final de la cola de READY.
+// Con VRR, los procesos que vuelven de un bloqueo sin haber consumido todo su quantum tienen prioridad sobre el resto
+// y solo ejecutan por el quantum que les quedaba.
+type schedulerRoundRobin struct {
+	service *Service
+	virtual bool
+}
+
+// Iniciar advierte si no se configuró el quantum
+func (s *schedulerRoundRobin) Iniciar() {
+	if s.quantumConfigurado() <= 0 {
+		s.service.Log.Warn("Quantum no configurado para RR/VRR, los procesos no serán desalojados")
+	}
+}
+
+// ElegirProximo devuelve el próximo proceso a ejecutar. Con VRR, los procesos con quantum restante (los que
+// volvieron de un bloqueo) se eligen antes que el resto.
+func (s *schedulerRoundRobin) ElegirProximo(ready []*internal.Proceso) *internal.Proceso {
+	if s.virtual {
+		for _, proc := range ready {
+			if proc != nil && proc.PCB != nil && proc.PCB.QuantumRestante > 0 {
+				return proc
+			}
+		}
+	}
+
+	return ready[0]
+}
+
+func (s *schedulerRoundRobin) AlIngresarReady(*internal.Proceso) {}
+
+// AlBloquearse guarda en el PCB el quantum que el proceso no llegó a consumir antes de bloquearse, para que VRR lo
+// priorice cuando vuelva a READY
+func (s *schedulerRoundRobin) AlBloquearse(proceso *internal.Proceso) {
+	if !s.virtual || s.quantumConfigurado() == 0 {
+		return
+	}
+
+	tiempoExec := proceso.PCB.MetricasTiempo[internal.EstadoExec]
+	if tiempoExec == nil {
+		return
+	}
+
+	restante := proceso.PCB.QuantumRestante - int(clock.Since(tiempoExec.TiempoInicio).Milliseconds())
+	if restante < 0 {
+		restante = 0
+	}
+	proceso.PCB.QuantumRestante = restante
+
+	s.service.Log.Debug("Quantum restante registrado (VRR)",
+		log.IntAttr("pid", proceso.PCB.PID),
+		log.IntAttr("quantum_restante", restante),
+	)
+}
+
+// AlSerDesalojado descarta el quantum restante del proceso que consumió todo su quantum
+func (s *schedulerRoundRobin) AlSerDesalojado(proceso *internal.Proceso, finDeQuantum bool) {
+	if finDeQuantum {
+		proceso.PCB.QuantumRestante = 0
+	}
+}
+
+func (s *schedulerRoundRobin) DebeDesalojar(*internal.Proceso, []*internal.Proceso) *internal.Proceso {
+	return nil
+}
+
+// Quantum devuelve el quantum configurado, o con VRR, el que le quedaba al proceso si volvió de un bloqueo
+func (s *schedulerRoundRobin) Quantum(proceso *internal.Proceso) int {
+	quantum := s.quantumConfigurado()
+
+	if s.virtual && quantum > 0 {
+		if proceso.PCB.QuantumRestante > 0 {
+			quantum = proceso.PCB.QuantumRestante
+		}
+		// Mientras ejecuta, el quantum restante es el asignado para esta ráfaga
+		proceso.PCB.QuantumRestante = quantum
+	}
+
+	return quantum
+}
+
+func (s *schedulerRoundRobin) quantumConfigurado() int {
+	if s.service.RoundRobinConfig == nil {
+		return 0
+	}
+
+	return s.service.RoundRobinConfig.Quantum
+}
+
+// iniciarQuantum programa el fin de quantum del proceso que acaba de pasar a EXEC. Al vencer, se envía una
+// interrupción de desalojo a la CPU que lo está ejecutando. Devuelve una función que cancela el temporizador e
+// informa si el quantum llegó a vencer.
+func (p *Service) iniciarQuantum(proceso *internal.Proceso, cpuAsignada *cpu.Cpu) func() bool {
+	quantum := p.Scheduler.Quantum(proceso)
+	if quantum <= 0 {
+		return func() bool { return false }
+	}
+
+	pid := proceso.PCB.PID
+	var vencido atomic.Bool
+
+	timer := clock.AfterFunc(time.Duration(quantum)*time.Millisecond, func() {
+		// Verificar que la CPU siga ejecutando al mismo proceso
+		p.mutexCPUsConectadas.RLock()
+		sigueEjecutando := cpuAsignada.Proceso.PID == pid
+		p.mutexCPUsConectadas.RUnlock()
+		if !sigueEjecutando {
+			return
+		}
+
+		vencido.Store(true)
+		p.Log.Debug("Fin de quantum, enviando interrupción de desalojo",
+			log.IntAttr("pid", pid),
+			log.StringAttr("cpu_id", cpuAsignada.ID),
+			log.IntAttr("quantum", quantum),
+		)
+		cpuAsignada.EnviarInterrupcion("Desalojo", false)
+	})
+
+	return func() bool {
+		timer.Stop()
+		return vencido.Load()
+	}
+}
+
+// devolverAReadyPorQuantum mueve un proceso de EXEC a READY luego de que la CPU lo devolviera por fin de quantum
+func (p *Service) devolverAReadyPorQuantum(proceso *internal.Proceso) {
+	if proceso == nil || proceso.PCB == nil {
+		return
+	}
+
+	// Remover de ExecQueue. Si ya no está, el proceso se bloqueó o finalizó antes de que llegue la interrupción
+	p.mutexExecQueue.Lock()
+	var found bool
+	p.Planificador.ExecQueue, found = p.removerDeCola(proceso.PCB.PID, p.Planificador.ExecQueue)
+	if found {
+		proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoAcumulado +=
+			clock.Since(proceso.PCB.MetricasTiempo[internal.EstadoExec].TiempoInicio)
+	}
+	p.mutexExecQueue.Unlock()
+
+	if !found {
+		p.Log.Debug("El proceso ya no se encuentra en Exec, no se lo devuelve a Ready por fin de quantum",
+			log.IntAttr("pid", proceso.PCB.PID),
+		)
+		return
+	}
+
+	// Consumió todo su quantum
+	p.Scheduler.AlSerDesalojado(proceso, true)
+
+	// Log obligatorio: Desalojo por fin de Quantum
+	// "## (<PID>) - Desalojado por fin de Quantum"
+	p.Log.Info(fmt.Sprintf("## (%d) - Desalojado por fin de Quantum", proceso.PCB.PID))
+	metricaDesalojos.Inc(MotivoDesalojoQuantum)
+
+	p.mutexReadyQueue.Lock()
+	p.agregarAReady(proceso)
+	p.mutexReadyQueue.Unlock()
+
+	//Log obligatorio: Cambio de estado
+	// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
+	p.registrarCambioEstado(proceso.PCB.PID, internal.EstadoExec, internal.EstadoReady, "")
+
+	p.canalNuevoProcesoReady <- struct{}{}
+}
@@ -0,0 +1,102 @@
+package planificadores
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+)
+
+func TestRoundRobin_ElegirProximo(t *testing.T) {
+	tests := []struct {
+		nombre    string
+		virtual   bool
+		restantes []int // Quantum restante de cada proceso en READY, con PIDs 1, 2, 3...
+		elegido   int
+	}{
+		{nombre: "RR elige al primero en llegar", restantes: []int{0, 0, 0}, elegido: 1},
+		{nombre: "RR ignora el quantum restante", restantes: []int{0, 50, 0}, elegido: 1},
+		{nombre: "VRR prioriza al que volvió de un bloqueo", virtual: true, restantes: []int{0, 50, 20}, elegido: 2},
+		{nombre: "VRR sin quantum restante elige al primero", virtual: true, restantes: []int{0, 0}, elegido: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			s := &schedulerRoundRobin{service: &Service{}, virtual: tt.virtual}
+
+			var ready []*internal.Proceso
+			for i, restante := range tt.restantes {
+				ready = append(ready, &internal.Proceso{PCB: &internal.PCB{PID: i + 1, QuantumRestante: restante}})
+			}
+
+			if elegido := s.ElegirProximo(ready); elegido.PCB.PID != tt.elegido {
+				t.Errorf("elegido = %d, se esperaba %d", elegido.PCB.PID, tt.elegido)
+			}
+			if desalojado := s.DebeDesalojar(ready[0], ready[1:]); desalojado != nil {
+				t.Errorf("DebeDesalojar() = %d, RR nunca desaloja por llegada a READY", desalojado.PCB.PID)
+			}
+		})
+	}
+}
+
+func TestRoundRobin_QuantumRestanteVRR(t *testing.T) {
+	reloj := clock.NewVirtual(time.Unix(0, 0))
+	clock.Usar(reloj)
+	defer clock.Usar(clock.Real{})
+
+	tests := []struct {
+		nombre    string
+		virtual   bool
+		restante  int           // Quantum restante al volver a EXEC
+		ejecuta   time.Duration // Tiempo que ejecuta antes de bloquearse
+		quantum   int           // Quantum de la ráfaga
+		alVolver  int           // Quantum restante luego de bloquearse
+		desalojar bool          // Si en lugar de bloquearse consume todo su quantum
+	}{
+		{nombre: "RR siempre usa el quantum configurado", restante: 40, ejecuta: 30 * time.Millisecond,
+			quantum: 100, alVolver: 40},
+		{nombre: "VRR guarda lo que no consumió", virtual: true, ejecuta: 30 * time.Millisecond,
+			quantum: 100, alVolver: 70},
+		{nombre: "VRR ejecuta solo lo que le quedaba", virtual: true, restante: 70, ejecuta: 50 * time.Millisecond,
+			quantum: 70, alVolver: 20},
+		{nombre: "VRR no guarda un quantum negativo", virtual: true, restante: 20, ejecuta: 50 * time.Millisecond,
+			quantum: 20, alVolver: 0},
+		{nombre: "VRR descarta el restante al vencer el quantum", virtual: true, restante: 20, quantum: 20,
+			desalojar: true, alVolver: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			s := &schedulerRoundRobin{
+				service: &Service{
+					Log:              slog.New(slog.NewTextHandler(io.Discard, nil)),
+					RoundRobinConfig: &RoundRobinConfig{Quantum: 100},
+				},
+				virtual: tt.virtual,
+			}
+			proceso := &internal.Proceso{PCB: &internal.PCB{
+				PID:             1,
+				QuantumRestante: tt.restante,
+				MetricasTiempo:  map[internal.Estado]*internal.EstadoTiempo{internal.EstadoExec: {TiempoInicio: clock.Now()}},
+			}}
+
+			if quantum := s.Quantum(proceso); quantum != tt.quantum {
+				t.Errorf("Quantum() = %d, se esperaba %d", quantum, tt.quantum)
+			}
+
+			reloj.Sleep(tt.ejecuta)
+			if tt.desalojar {
+				s.AlSerDesalojado(proceso, true)
+			} else {
+				s.AlBloquearse(proceso)
+			}
+
+			if proceso.PCB.QuantumRestante != tt.alVolver {
+				t.Errorf("quantum restante = %d, se esperaba %d", proceso.PCB.QuantumRestante, tt.alVolver)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package planificadores
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+// Scheduler es una política de planificación de corto plazo. El Service se encarga del ciclo de despacho y de mover
+// los procesos entre colas actualizando sus métricas; el Scheduler solo decide a quién ejecutar, a quién desalojar y
+// por cuánto tiempo.
+type Scheduler interface {
+	// ElegirProximo devuelve el próximo proceso a ejecutar. Puede reordenar la cola.
+	// IMPORTANTE: Se llama con el mutex de ReadyQueue (o el de la cola de la CPU, con colas por CPU) bloqueado y la
+	// cola no vacía
+	ElegirProximo(ready []*internal.Proceso) *internal.Proceso
+
+	// AlIngresarReady se llama cada vez que un proceso entra a READY.
+	// IMPORTANTE: Se llama con el mutex de ReadyQueue bloqueado
+	AlIngresarReady(proceso *internal.Proceso)
+
+	// AlBloquearse se llama cuando un proceso abandona EXEC por una syscall bloqueante (IO, DUMP_MEMORY)
+	AlBloquearse(proceso *internal.Proceso)
+
+	// AlSerDesalojado se llama cuando un proceso vuelve de EXEC a READY, ya sea porque lo desalojó otro proceso o
+	// porque consumió todo su quantum
+	AlSerDesalojado(proceso *internal.Proceso, finDeQuantum bool)
+
+	// DebeDesalojar devuelve el proceso en ejecución que debe ser desalojado para ejecutar al proceso nuevo, o nil si
+	// no corresponde desalojar a ninguno.
+	// IMPORTANTE: Se llama con el mutex de ExecQueue bloqueado
+	DebeDesalojar(procesoNuevo *internal.Proceso, exec []*internal.Proceso) *internal.Proceso
+
+	// Quantum devuelve el quantum en milisegundos de la ráfaga que está por comenzar, o 0 si no se desaloja por
+	// fin de quantum
+	Quantum(proceso *internal.Proceso) int
+}
+
+// SchedulerConInicio lo implementan los Scheduler que necesitan tareas en segundo plano (por ejemplo, el boost de MLFQ)
+type SchedulerConInicio interface {
+	Iniciar()
+}
+
+// SchedulerConMetricas lo implementan los Scheduler que agregan información propia al log de métricas de estado
+type SchedulerConMetricas interface {
+	Metricas(proceso *internal.Proceso) string
+}
+
+// SchedulerConFinalizacion lo implementan los Scheduler que guardan información por proceso y deben olvidarla
+// cuando el proceso finaliza
+type SchedulerConFinalizacion interface {
+	// AlFinalizar se llama después de escribir las métricas de estado del proceso que pasó a EXIT
+	AlFinalizar(proceso *internal.Proceso)
+}
+
+// ConstructorScheduler crea una instancia de un Scheduler para el Service dado
+type ConstructorScheduler func(p *Service) Scheduler
+
+var (
+	registroSchedulers      = make(map[string]ConstructorScheduler)
+	mutexRegistroSchedulers sync.RWMutex
+)
+
+// RegistrarScheduler agrega una política de corto plazo al registro, para que pueda elegirse por nombre desde el
+// campo scheduler_algorithm de la configuración. Si ya existía una con el mismo nombre, se reemplaza.
+func RegistrarScheduler(nombre string, constructor ConstructorScheduler) {
+	mutexRegistroSchedulers.Lock()
+	defer mutexRegistroSchedulers.Unlock()
+
+	registroSchedulers[nombre] = constructor
+}
+
+func init() {
+	RegistrarScheduler("FIFO", func(p *Service) Scheduler { return &schedulerFIFO{} })
+	RegistrarScheduler("SJF", func(p *Service) Scheduler { return &schedulerSJF{service: p} })
+	RegistrarScheduler("SRT", func(p *Service) Scheduler { return &schedulerSJF{service: p, desalojo: true} })
+	RegistrarScheduler("RR", func(p *Service) Scheduler { return &schedulerRoundRobin{service: p} })
+	RegistrarScheduler("VRR", func(p *Service) Scheduler { return &schedulerRoundRobin{service: p, virtual: true} })
+	RegistrarScheduler("MLFQ", func(p *Service) Scheduler { return &schedulerMLFQ{service: p} })
+	RegistrarScheduler("PRIORIDADES", func(p *Service) Scheduler {
+		return &schedulerPrioridades{service: p}
+	})
+	RegistrarScheduler("PRIORIDADES_DESALOJO", func(p *Service) Scheduler {
+		return &schedulerPrioridades{service: p, desalojo: true}
+	})
+	RegistrarScheduler("LOTTERY", func(p *Service) Scheduler { return nuevoSchedulerProporcional(p, false) })
+	RegistrarScheduler("STRIDE", func(p *Service) Scheduler { return nuevoSchedulerProporcional(p, true) })
+}
+
+// nuevoScheduler crea el Scheduler registrado con el nombre dado. Si no existe, se usa FIFO.
+func (p *Service) nuevoScheduler(nombre string) Scheduler {
+	mutexRegistroSchedulers.RLock()
+	constructor, ok := registroSchedulers[nombre]
+	mutexRegistroSchedulers.RUnlock()
+
+	if !ok {
+		p.Log.Warn("Algoritmo de corto plazo no reconocido, se usará FIFO",
+			log.StringAttr("algoritmo", nombre),
+		)
+		return &schedulerFIFO{}
+	}
+
+	return constructor(p)
+}
+
+// PlanificadorCortoPlazo inicia el ciclo de despacho con el Scheduler configurado
+func (p *Service) PlanificadorCortoPlazo() {
+	if s, ok := p.Scheduler.(SchedulerConInicio); ok {
+		s.Iniciar()
+	}
+
+	go p.cicloDespacho()
+}
+
+// cicloDespacho es el único ciclo del planificador de corto plazo. Mientras haya procesos en READY:
+// * Si hay una CPU libre, se le asigna el proceso que elija el Scheduler (de su propia cola, si hay colas por CPU).
+// * Si no, se le consulta al Scheduler si el proceso elegido debe desalojar a alguno en ejecución.
+// * Si no hay nada para hacer, espera a que llegue un proceso a READY o a que se libere una CPU.
+func (p *Service) cicloDespacho() {
+	for {
+		// Con la planificación detenida no se despachan procesos
+		p.esperarPlanificacionActiva()
+
+		p.mutexReadyQueue.Lock()
+		if len(p.Planificador.ReadyQueue) == 0 {
+			p.mutexReadyQueue.Unlock()
+			// No hay procesos, esperar por notificación
+			p.Log.Debug("No hay procesos en ReadyQueue, esperando notificación...",
+				log.StringAttr("algoritmo", p.ShortTermAlgorithm),
+			)
+			<-p.canalNuevoProcesoReady
+			continue
+		}
+
+		procesoElegido := p.Scheduler.ElegirProximo(p.Planificador.ReadyQueue)
+		p.mutexReadyQueue.Unlock()
+
+		// Si hay CPUs libres, se asigna directamente
+		if p.CantidadDeCpusDisponibles() > 0 {
+			if cpuLibre := p.BuscarCPUDisponible(); cpuLibre != nil {
+				// Se vuelve a elegir al proceso, ya que la cola pudo cambiar mientras se adquiría la CPU
+				procesoElegido = p.elegirProcesoParaCPU(cpuLibre)
+				if procesoElegido == nil {
+					// Ninguna cola tiene procesos para despachar: se espera a que llegue uno en vez de reintentar
+					p.LiberarCPU(cpuLibre)
+					<-p.canalNuevoProcesoReady
+					continue
+				}
+				if !p.asignarProcesoACPU(procesoElegido, cpuLibre) {
+					p.LiberarCPU(cpuLibre)
+				}
+			}
+			continue
+		}
+
+		// Si no, se evalúa si desaloja a algún proceso en ejecución
+		p.mutexSRT.Lock()
+		desalojo := p.evaluarDesalojo(procesoElegido)
+		p.mutexSRT.Unlock()
+		if desalojo {
+			continue
+		}
+
+		// Nada para hacer: esperar a que llegue un proceso nuevo o se libere una CPU
+		select {
+		case <-p.canalNuevoProcesoReady:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// agregarAReady agrega un proceso al final de ReadyQueue, actualizando sus métricas de READY y avisando al Scheduler.
+// No notifica al planificador de corto plazo ni escribe el log de cambio de estado, eso queda a cargo de quien llama.
+// IMPORTANTE: El mutex de ReadyQueue debe estar ya bloqueado por quien llama esta función
+func (p *Service) agregarAReady(proceso *internal.Proceso) {
+	p.Planificador.ReadyQueue = append(p.Planificador.ReadyQueue, proceso)
+
+	if proceso.PCB.MetricasTiempo[internal.EstadoReady] == nil {
+		proceso.PCB.MetricasTiempo[internal.EstadoReady] = &internal.EstadoTiempo{}
+	}
+	proceso.PCB.MetricasTiempo[internal.EstadoReady].TiempoInicio = clock.Now()
+	proceso.PCB.MetricasEstado[internal.EstadoReady]++
+
+	p.Scheduler.AlIngresarReady(proceso)
+
+	if p.ColasPorCPU {
+		p.encolarEnCPU(proceso)
+	}
+}
+
+// olvidarEnScheduler avisa al Scheduler que el proceso finalizó, para que libere lo que guardaba de él
+func (p *Service) olvidarEnScheduler(proceso *internal.Proceso) {
+	if s, ok := p.Scheduler.(SchedulerConFinalizacion); ok {
+		s.AlFinalizar(proceso)
+	}
+}
+
+// registrarBloqueo avisa al Scheduler que un proceso abandona EXEC por una syscall bloqueante.
+// Debe llamarse antes de actualizar las métricas de EXEC.
+func (p *Service) registrarBloqueo(proceso *internal.Proceso) {
+	if proceso == nil || proceso.PCB == nil {
+		return
+	}
+
+	p.Scheduler.AlBloquearse(proceso)
+}
+
+// metricasAdicionales devuelve la información propia del Scheduler y de las colas por CPU para agregar al log de
+// métricas de estado
+func (p *Service) metricasAdicionales(proceso *internal.Proceso) string {
+	var metricas string
+	if s, ok := p.Scheduler.(SchedulerConMetricas); ok {
+		metricas = s.Metricas(proceso)
+	}
+
+	metricas += p.metricasAfinidad(proceso)
+	if proceso.PCB.MotivoFinalizacion != "" {
+		metricas += fmt.Sprintf(", MOTIVO %s", proceso.PCB.MotivoFinalizacion)
+	}
+
+	return metricas
+}
+
+// schedulerFIFO ejecuta los procesos en orden de llegada a READY, sin desalojo
+type schedulerFIFO struct{}
+
+func (s *schedulerFIFO) ElegirProximo(ready []*internal.Proceso) *internal.Proceso {
+	return ready[0]
+}
+
+func (s *schedulerFIFO) AlIngresarReady(*internal.Proceso) {}
+
+func (s *schedulerFIFO) AlBloquearse(*internal.Proceso) {}
+
+func (s *schedulerFIFO) AlSerDesalojado(*internal.Proceso, bool) {}
+
+func (s *schedulerFIFO) DebeDesalojar(*internal.Proceso, []*internal.Proceso) *internal.Proceso {
+	return nil
+}
+
+func (s *schedulerFIFO) Quantum(*internal.Proceso) int {
+	return 0
+}
+
+// ordenarEstable ordena la cola de READY con el criterio dado, respetando el orden de llegada entre los procesos
+// equivalentes
+func ordenarEstable(ready []*internal.Proceso, menor func(a, b *internal.Proceso) bool) {
+	sort.SliceStable(ready, func(i, j int) bool {
+		return menor(ready[i], ready[j])
+	})
+}
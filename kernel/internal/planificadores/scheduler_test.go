@@ -0,0 +1,50 @@
+package planificadores
+
+import (
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestScheduler_Registro(t *testing.T) {
+	tests := []struct {
+		algoritmo string
+		esperado  Scheduler
+	}{
+		{"FIFO", &schedulerFIFO{}},
+		{"SRT", &schedulerSJF{desalojo: true}},
+		{"RR", &schedulerRoundRobin{}},
+		{"VRR", &schedulerRoundRobin{virtual: true}},
+		{"MLFQ", &schedulerMLFQ{}},
+		{"PRIORIDADES_DESALOJO", &schedulerPrioridades{desalojo: true}},
+		{"STRIDE", &schedulerProporcional{stride: true}},
+		{"NO_EXISTE", &schedulerFIFO{}}, // Un algoritmo desconocido usa FIFO
+	}
+
+	p := &Service{Log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	for _, tt := range tests {
+		t.Run(tt.algoritmo, func(t *testing.T) {
+			s := p.nuevoScheduler(tt.algoritmo)
+			if reflect.TypeOf(s) != reflect.TypeOf(tt.esperado) {
+				t.Fatalf("nuevoScheduler(%q) = %T, se esperaba %T", tt.algoritmo, s, tt.esperado)
+			}
+
+			// Las variantes de un mismo Scheduler se distinguen por su configuración
+			var variante, esperada bool
+			switch s := s.(type) {
+			case *schedulerSJF:
+				variante, esperada = s.desalojo, tt.esperado.(*schedulerSJF).desalojo
+			case *schedulerRoundRobin:
+				variante, esperada = s.virtual, tt.esperado.(*schedulerRoundRobin).virtual
+			case *schedulerPrioridades:
+				variante, esperada = s.desalojo, tt.esperado.(*schedulerPrioridades).desalojo
+			case *schedulerProporcional:
+				variante, esperada = s.stride, tt.esperado.(*schedulerProporcional).stride
+			}
+			if variante != esperada {
+				t.Errorf("nuevoScheduler(%q) creó la variante equivocada", tt.algoritmo)
+			}
+		})
+	}
+}
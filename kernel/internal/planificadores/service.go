@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/internal"
 	"github.com/sisoputnfrba/tp-golang/kernel/pkg/cpu"
@@ -12,29 +13,63 @@ import (
 )
 
 type Service struct {
-	Planificador           *Planificador
-	LargoPlazoAlgorithm    string // Algoritmo de largo plazo utilizado
-	ShortTermAlgorithm     string // Algoritmo de corto plazo utilizado
-	Log                    *slog.Logger
-	Memoria                *memoria.Memoria
-	CPUsConectadas         []*cpu.Cpu
-	CanalEnter             chan struct{}
-	canalNuevoProcesoReady chan struct{}
-	CanalNuevoProcesoNew   chan *internal.Proceso // Canal para recibir notificaciones de nuevos procesos en NewQueue
-	CanalNuevoProcBlocked  chan *internal.Proceso
-	CanalNewProcSuspReady  chan *internal.Proceso
-	mutexNewQueue          *sync.RWMutex
-	mutexReadyQueue        *sync.RWMutex
-	mutexCPUsConectadas    *sync.RWMutex
-	mutexBlockQueue        *sync.RWMutex
-	mutexExecQueue         *sync.RWMutex
-	mutexSuspBlockQueue    *sync.RWMutex
-	mutexSuspReadyQueue    *sync.RWMutex
-	mutexSRT               *sync.RWMutex // Mutex para proteger el acceso a las colas de procesos
-	SjfConfig              *SjfConfig
-	MedianoPlazoConfig     *MedianoPlazoConfig
-	CPUSemaphore           chan struct{} // Semáforo contador para CPUs disponibles
-	HttpClient             *http.Client
+	Planificador               *Planificador
+	LargoPlazoAlgorithm        string             // Algoritmo de largo plazo utilizado
+	PoliticaAdmision           PoliticaAdmision   // Política de largo plazo elegida según LargoPlazoAlgorithm
+	PoliticaSuspension         PoliticaSuspension // Política de mediano plazo elegida según MedianoPlazoConfig.Politica
+	Temporizadores             *Temporizadores    // Suspensiones por tiempo programadas, por PID
+	PoliticaHijos              string             // Qué pasa con los hijos de un proceso que finaliza (ORPHAN, CASCADE)
+	DeadlockConfig             *DeadlockConfig    // Cuándo se buscan deadlocks entre los recursos y cómo se resuelven
+	arbol                      *arbolProcesos     // Padres, hijos y esperas por WAIT de los procesos vivos
+	ShortTermAlgorithm         string             // Algoritmo de corto plazo utilizado
+	Scheduler                  Scheduler          // Política de corto plazo elegida según ShortTermAlgorithm
+	ColasPorCPU                bool               // Si cada CPU tiene su propia cola de READY, con afinidad y work stealing
+	Log                        *slog.Logger
+	Memoria                    *memoria.Memoria
+	CPUsConectadas             []*cpu.Cpu
+	EstadoPlanificacion        string // PlanificadorEstadoStop o PlanificadorEstadoStart, protegido por mutexPlanificacion
+	GradoMultiprogramacion     int    // Máximo de procesos en READY, EXEC y BLOCKED (0 sin límite), protegido por mutexPlanificacion
+	esperandoMultiprogramacion int    // Procesos que no se admiten solo por el grado, protegido por mutexPlanificacion
+	canalNuevoProcesoReady     chan struct{}
+	CanalNuevoProcesoNew       chan *internal.Proceso // Canal para recibir notificaciones de nuevos procesos en NewQueue
+	CanalNuevoProcBlocked      chan *internal.Proceso
+	CanalNewProcSuspReady      chan *internal.Proceso
+	mutexNewQueue              *sync.RWMutex
+	mutexReadyQueue            *sync.RWMutex
+	mutexCPUsConectadas        *sync.RWMutex
+	mutexBlockQueue            *sync.RWMutex
+	mutexExecQueue             *sync.RWMutex
+	mutexSuspBlockQueue        *sync.RWMutex
+	mutexSuspReadyQueue        *sync.RWMutex
+	mutexSRT                   *sync.RWMutex // Mutex para proteger el acceso a las colas de procesos
+	mutexPlanificacion         *sync.Mutex
+	condPlanificacion          *sync.Cond // Avisa cuando se inicia la planificación
+	mutexFinesIO               *sync.Mutex
+	finesIO                    map[int]time.Time // Fin previsto de la IO en curso de cada proceso, según el reloj del kernel
+	mutexRecursos              *sync.Mutex
+	recursos                   map[string]*recurso    // Semáforos del kernel por nombre (WAIT/SIGNAL)
+	asignaciones               map[int]map[string]int // Instancias de cada recurso que tiene asignadas cada proceso
+	mutexMailboxes             *sync.Mutex
+	mailboxes                  map[string]*mailbox       // Mailboxes de SEND/RECV por nombre (o PID)
+	entregasPendientes         map[int]entregaPendiente  // Mensajes recibidos por procesos swappeados, por PID
+	entregando                 map[int]bool              // Procesos en BLOCKED en cuya memoria se escribe un mensaje, protegido por mutexBlockQueue
+	capacidadMailbox           int                       // Mensajes que entran en cada mailbox
+	suspensionesManuales       map[int]*suspensionManual // Procesos suspendidos desde la API, protegido por mutexSuspBlockQueue
+	mutexColasCPU              *sync.RWMutex
+	colasCPU                   map[string]*colaCPU // Colas de READY por ID de CPU, si ColasPorCPU
+	colaDeProceso              map[int]*colaCPU    // Cola de CPU en la que está cada proceso en READY, por PID
+	mutexDespachos             *sync.Mutex
+	despachos                  map[int]*despacho // Ráfagas en curso por PID, esperando que la CPU devuelva el contexto
+	SjfConfig                  *SjfConfig
+	MedianoPlazoConfig         *MedianoPlazoConfig
+	RoundRobinConfig           *RoundRobinConfig
+	MLFQConfig                 *MLFQConfig
+	PrioridadesConfig          *PrioridadesConfig
+	ProporcionalConfig         *ProporcionalConfig
+	CPUSemaphore               chan struct{} // Semáforo contador para CPUs disponibles
+	HttpClient                 *http.Client
+	Timeline                   *Timeline // Registro de los cambios de estado de los procesos
+	Reporte                    *Reporte  // Reporte de los procesos finalizados, nil si no se configuró reporte_path
 }
 
 type Planificador struct {
@@ -59,15 +94,36 @@ type SjfConfig struct {
 	InitialEstimate int     `json:"initial_estimate"`
 }
 
+type RoundRobinConfig struct {
+	Quantum int `json:"quantum"` // Quantum en milisegundos
+}
+
+type MLFQConfig struct {
+	Quantums []int `json:"mlfq_quantums"` // Quantum en milisegundos de cada nivel, del 0 (mayor prioridad) en adelante
+	Boost    int   `json:"mlfq_boost"`    // Período en milisegundos con el que todos los procesos vuelven al nivel 0
+}
+
+type PrioridadesConfig struct {
+	AgingThreshold int `json:"aging_threshold"` // Tiempo en milisegundos en READY tras el cual un proceso gana prioridad
+}
+
+type ProporcionalConfig struct {
+	TicketsPorDefecto int   `json:"default_tickets"` // Tickets de los procesos que no los indican en INIT_PROC
+	Semilla           int64 `json:"rng_seed"`        // Semilla del sorteo de LOTTERY; con 0 se usa una semilla aleatoria
+}
+
 type MedianoPlazoConfig struct {
-	SuspensionTime int `json:"suspension_time"` // Tiempo de suspensión en milisegundos
+	SuspensionTime int    `json:"suspension_time"`   // Tiempo de suspensión en milisegundos (política TIMER)
+	Politica       string `json:"suspension_policy"` // TIMER, LARGEST, LONGEST_BLOCKED o LOWEST_PRIORITY
 }
 
 // NewPlanificador función que sirve para crear una nueva instancia del planificador de procesos. El planificador posee
 // varias colas para gestionar los procesos en diferentes estados: New, Ready, Block, Suspended Ready, Suspended Block, Exec y Exit.
 func NewPlanificador(log *slog.Logger, ipMemoria, largoPlazoAlgoritmo, cortoPlazoAlgoritmo string,
-	puertoMemoria int, sjfConfig *SjfConfig, suspTime int, httpClient *http.Client) *Service {
-	return &Service{
+	puertoMemoria int, sjfConfig *SjfConfig, medianoPlazoConfig *MedianoPlazoConfig, rrConfig *RoundRobinConfig, mlfqConfig *MLFQConfig,
+	prioridadesConfig *PrioridadesConfig, proporcionalConfig *ProporcionalConfig, colasPorCPU bool,
+	gradoMultiprogramacion int, httpClient *http.Client) *Service {
+	s := &Service{
 		Planificador: &Planificador{
 			NewQueue:       make([]*internal.Proceso, 0),
 			ReadyQueue:     make([]*internal.Proceso, 0),
@@ -80,7 +136,8 @@ func NewPlanificador(log *slog.Logger, ipMemoria, largoPlazoAlgoritmo, cortoPlaz
 		Log:                    log,
 		Memoria:                memoria.NewMemoria(ipMemoria, puertoMemoria, log),
 		CPUsConectadas:         make([]*cpu.Cpu, 0),
-		CanalEnter:             make(chan struct{}),
+		EstadoPlanificacion:    PlanificadorEstadoStop,
+		GradoMultiprogramacion: gradoMultiprogramacion,
 		SjfConfig:              sjfConfig,
 		LargoPlazoAlgorithm:    largoPlazoAlgoritmo,
 		ShortTermAlgorithm:     cortoPlazoAlgoritmo,
@@ -96,13 +153,44 @@ func NewPlanificador(log *slog.Logger, ipMemoria, largoPlazoAlgoritmo, cortoPlaz
 		mutexSuspReadyQueue:    &sync.RWMutex{},
 		mutexCPUsConectadas:    &sync.RWMutex{},
 		mutexSRT:               &sync.RWMutex{}, // Mutex para proteger el acceso a las colas de procesos en SRT
-		MedianoPlazoConfig: &MedianoPlazoConfig{
-			SuspensionTime: suspTime,
-		},
-		CPUSemaphore: make(chan struct{}, 100), // Inicializamos el semáforo vacío, se llenará cuando se conecten CPUp.
+		mutexFinesIO:           &sync.Mutex{},
+		finesIO:                make(map[int]time.Time),
+		mutexRecursos:          &sync.Mutex{},
+		recursos:               make(map[string]*recurso),
+		asignaciones:           make(map[int]map[string]int),
+		mutexMailboxes:         &sync.Mutex{},
+		mailboxes:              make(map[string]*mailbox),
+		entregasPendientes:     make(map[int]entregaPendiente),
+		entregando:             make(map[int]bool),
+		capacidadMailbox:       CapacidadMailboxPorDefecto,
+		suspensionesManuales:   make(map[int]*suspensionManual),
+		mutexColasCPU:          &sync.RWMutex{},
+		colasCPU:               make(map[string]*colaCPU),
+		colaDeProceso:          make(map[int]*colaCPU),
+		mutexDespachos:         &sync.Mutex{},
+		despachos:              make(map[int]*despacho),
+		MedianoPlazoConfig:     medianoPlazoConfig,
+		RoundRobinConfig:       rrConfig,
+		MLFQConfig:             mlfqConfig,
+		PrioridadesConfig:      prioridadesConfig,
+		ProporcionalConfig:     proporcionalConfig,
+		ColasPorCPU:            colasPorCPU,
+		CPUSemaphore:           make(chan struct{}, 100), // Inicializamos el semáforo vacío, se llenará cuando se conecten CPUp.
 		// Buffer máximo de 100 CPUs
-		HttpClient: httpClient,
+		HttpClient:     httpClient,
+		Timeline:       NewTimeline(),
+		Temporizadores: NewTemporizadores(),
+		PoliticaHijos:  PoliticaHijosHuerfanos,
+		arbol:          newArbolProcesos(),
 	}
+	s.mutexPlanificacion = &sync.Mutex{}
+	s.condPlanificacion = sync.NewCond(s.mutexPlanificacion)
+	s.Scheduler = s.nuevoScheduler(cortoPlazoAlgoritmo)
+	s.PoliticaAdmision = s.nuevaPoliticaAdmision(largoPlazoAlgoritmo)
+	s.PoliticaSuspension = s.nuevaPoliticaSuspension(medianoPlazoConfig.Politica)
+	s.registrarMetricas()
+
+	return s
 }
 
 func (p *Service) BuscarProcesoEnCualquierCola(pid int) (*internal.Proceso, internal.Estado) {
@@ -0,0 +1,271 @@
+package planificadores
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/log"
+)
+
+const PoliticaSuspensionPorTiempo = "TIMER" // Política por defecto: suspende luego de suspension_time en BLOCKED
+
+// PoliticaSuspension es una política del planificador de mediano plazo: decide cuándo se suspende un proceso
+// bloqueado. El Service se encarga de moverlo a SUSP.BLOCKED y de pedirle a memoria que lo swappee.
+type PoliticaSuspension interface {
+	// AlBloquearse se llama cada vez que un proceso pasa a BLOCKED
+	AlBloquearse(proceso *internal.Proceso)
+	// ElegirVictima devuelve el índice del proceso a suspender cuando un proceso de NEW o SUSP.READY no entra en
+	// memoria, o -1 si la política no suspende por falta de memoria. Recibe los procesos de BLOCKED que se pueden
+	// suspender, en orden de llegada, y nunca vacío.
+	// IMPORTANTE: Se llama con el mutex de BlockQueue bloqueado
+	ElegirVictima(bloqueados []*internal.Proceso) int
+}
+
+// ConstructorPoliticaSuspension crea una instancia de una PoliticaSuspension para el Service dado
+type ConstructorPoliticaSuspension func(p *Service) PoliticaSuspension
+
+var (
+	registroPoliticasSuspension      = make(map[string]ConstructorPoliticaSuspension)
+	mutexRegistroPoliticasSuspension sync.RWMutex
+)
+
+// RegistrarPoliticaSuspension agrega una política de mediano plazo al registro, para que pueda elegirse por nombre
+// desde el campo suspension_policy de la configuración. Si ya existía una con el mismo nombre, se reemplaza.
+func RegistrarPoliticaSuspension(nombre string, constructor ConstructorPoliticaSuspension) {
+	mutexRegistroPoliticasSuspension.Lock()
+	defer mutexRegistroPoliticasSuspension.Unlock()
+
+	registroPoliticasSuspension[nombre] = constructor
+}
+
+func init() {
+	RegistrarPoliticaSuspension(PoliticaSuspensionPorTiempo, func(p *Service) PoliticaSuspension {
+		return &suspensionPorTiempo{service: p}
+	})
+	RegistrarPoliticaSuspension("LARGEST", func(p *Service) PoliticaSuspension {
+		return suspensionPorPresion{criterio: masGrande}
+	})
+	RegistrarPoliticaSuspension("LONGEST_BLOCKED", func(p *Service) PoliticaSuspension {
+		return suspensionPorPresion{criterio: bloqueadoHaceMas}
+	})
+	RegistrarPoliticaSuspension("LOWEST_PRIORITY", func(p *Service) PoliticaSuspension {
+		return suspensionPorPresion{criterio: menorPrioridad}
+	})
+}
+
+// nuevaPoliticaSuspension crea la PoliticaSuspension registrada con el nombre dado. Sin nombre, o si no existe, se
+// usa la suspensión por tiempo.
+func (p *Service) nuevaPoliticaSuspension(nombre string) PoliticaSuspension {
+	if nombre == "" {
+		nombre = PoliticaSuspensionPorTiempo
+	}
+
+	mutexRegistroPoliticasSuspension.RLock()
+	constructor, ok := registroPoliticasSuspension[nombre]
+	mutexRegistroPoliticasSuspension.RUnlock()
+
+	if !ok {
+		p.Log.Warn("Política de suspensión no reconocida, se usará TIMER",
+			log.StringAttr("politica", nombre),
+		)
+		p.MedianoPlazoConfig.Politica = PoliticaSuspensionPorTiempo
+		return &suspensionPorTiempo{service: p}
+	}
+
+	p.MedianoPlazoConfig.Politica = nombre
+	return constructor(p)
+}
+
+// suspensionPorTiempo suspende a los procesos que siguen bloqueados luego de suspension_time, sin importar el estado
+// de la memoria
+type suspensionPorTiempo struct {
+	service *Service
+}
+
+// AlBloquearse programa la suspensión del proceso para dentro de suspension_time. Si el proceso ya tenía una
+// suspensión programada de un bloqueo anterior, se reemplaza.
+func (s *suspensionPorTiempo) AlBloquearse(proceso *internal.Proceso) {
+	if proceso == nil || proceso.PCB == nil {
+		return
+	}
+
+	espera := time.Duration(s.service.MedianoPlazoConfig.SuspensionTime) * time.Millisecond
+	s.service.Temporizadores.Programar(proceso.PCB.PID, espera, func(generacion uint64) {
+		s.vencer(proceso, generacion)
+	})
+}
+
+// vencer suspende al proceso si sigue en el mismo bloqueo para el que se programó el temporizador
+func (s *suspensionPorTiempo) vencer(proceso *internal.Proceso, generacion uint64) {
+	pid := proceso.PCB.PID
+	vigente := func() bool { return s.service.Temporizadores.Vigente(pid, generacion) }
+	if !s.service.suspenderBloqueadoSi(proceso, vigente) {
+		return
+	}
+
+	s.service.Log.Info(fmt.Sprintf("## (%d) - Suspendido por tiempo en BLOCKED", pid),
+		log.StringAttr("politica", PoliticaSuspensionPorTiempo),
+		log.IntAttr("suspension_time", s.service.MedianoPlazoConfig.SuspensionTime),
+	)
+
+	// Se liberó memoria, intentar traer procesos desde SUSP.READY o NEW sin demorar a los otros temporizadores
+	go s.service.CheckearEspacioEnMemoria()
+}
+
+func (s *suspensionPorTiempo) ElegirVictima([]*internal.Proceso) int {
+	return -1
+}
+
+// suspensionPorPresion no suspende por tiempo: cuando un proceso no entra en memoria, suspende al proceso bloqueado
+// que indique su criterio (el primero en caso de empate) y se vuelve a intentar la admisión
+type suspensionPorPresion struct {
+	criterio func(a, b *internal.Proceso) bool // Si a es mejor víctima que b
+}
+
+func (s suspensionPorPresion) AlBloquearse(*internal.Proceso) {}
+
+func (s suspensionPorPresion) ElegirVictima(bloqueados []*internal.Proceso) int {
+	elegido := 0
+	for i, proceso := range bloqueados {
+		if s.criterio(proceso, bloqueados[elegido]) {
+			elegido = i
+		}
+	}
+
+	return elegido
+}
+
+func masGrande(a, b *internal.Proceso) bool {
+	return a.PCB.TamanioBytes > b.PCB.TamanioBytes
+}
+
+func bloqueadoHaceMas(a, b *internal.Proceso) bool {
+	return inicioBloqueo(a).Before(inicioBloqueo(b))
+}
+
+// menorPrioridad elige al proceso de menor prioridad (mayor número). A igual prioridad, al bloqueado hace más tiempo.
+func menorPrioridad(a, b *internal.Proceso) bool {
+	if a.PCB.Prioridad != b.PCB.Prioridad {
+		return a.PCB.Prioridad > b.PCB.Prioridad
+	}
+
+	return bloqueadoHaceMas(a, b)
+}
+
+func inicioBloqueo(proceso *internal.Proceso) time.Time {
+	if tiempo := proceso.PCB.MetricasTiempo[internal.EstadoBloqueado]; tiempo != nil {
+		return tiempo.TiempoInicio
+	}
+
+	return time.Time{}
+}
+
+// suspenderPorFaltaDeMemoria suspende al proceso bloqueado que elija la política para hacerle lugar en memoria al
+// proceso que no entra. Devuelve false si la política no suspende por falta de memoria o si no hay a quién suspender.
+// IMPORTANTE: No debe llamarse con los mutex de BlockQueue o SuspBlockQueue bloqueados
+func (p *Service) suspenderPorFaltaDeMemoria(esperando *internal.Proceso) bool {
+	for {
+		victima := p.elegirVictimaSuspension()
+		if victima == nil {
+			p.Log.Debug("No hay procesos bloqueados para suspender por falta de memoria",
+				log.IntAttr("pid_esperando", esperando.PCB.PID),
+				log.StringAttr("politica", p.MedianoPlazoConfig.Politica),
+			)
+			return false
+		}
+
+		// Si la víctima dejó BLOCKED mientras se la elegía, se elige otra
+		if p.suspenderBloqueado(victima) {
+			p.Log.Info(fmt.Sprintf("## (%d) - Suspendido por falta de memoria para el proceso %d",
+				victima.PCB.PID, esperando.PCB.PID),
+				log.StringAttr("politica", p.MedianoPlazoConfig.Politica),
+				log.IntAttr("tamanio_victima", victima.PCB.TamanioBytes),
+				log.IntAttr("tamanio_esperando", esperando.PCB.TamanioBytes),
+			)
+			return true
+		}
+	}
+}
+
+// elegirVictimaSuspension devuelve el proceso de BLOCKED que la política elige suspender, o nil si no elige ninguno.
+// Los procesos bloqueados por DUMP_MEMORY no se suspenden.
+func (p *Service) elegirVictimaSuspension() *internal.Proceso {
+	p.mutexBlockQueue.RLock()
+	defer p.mutexBlockQueue.RUnlock()
+
+	candidatos := make([]*internal.Proceso, 0, len(p.Planificador.BlockQueue))
+	for _, proceso := range p.Planificador.BlockQueue {
+		if proceso == nil || proceso.PCB == nil {
+			continue
+		}
+		if ultimo, ok := p.Timeline.UltimoEvento(proceso.PCB.PID); ok && ultimo.Recurso == RecursoDumpMemory {
+			continue
+		}
+		candidatos = append(candidatos, proceso)
+	}
+
+	if len(candidatos) == 0 {
+		return nil
+	}
+
+	indice := p.PoliticaSuspension.ElegirVictima(candidatos)
+	if indice < 0 || indice >= len(candidatos) {
+		return nil
+	}
+
+	return candidatos[indice]
+}
+
+// suspenderBloqueado mueve un proceso de BLOCKED a SUSP.BLOCKED y le pide a memoria que lo swappee. Devuelve false si
+// el proceso ya no estaba en BLOCKED.
+func (p *Service) suspenderBloqueado(proceso *internal.Proceso) bool {
+	return p.suspenderBloqueadoSi(proceso, nil)
+}
+
+// suspenderBloqueadoSi es como suspenderBloqueado, pero solo suspende si la condición se cumple. La condición se
+// evalúa con el mutex de BlockQueue bloqueado, por lo que el proceso no puede salir de BLOCKED mientras tanto.
+func (p *Service) suspenderBloqueadoSi(proceso *internal.Proceso, condicion func() bool) bool {
+	pid := proceso.PCB.PID
+
+	p.mutexBlockQueue.Lock()
+	if p.entregando[pid] || (condicion != nil && !condicion()) {
+		p.mutexBlockQueue.Unlock()
+		return false
+	}
+	var removido bool
+	p.Planificador.BlockQueue, removido = p.removerDeCola(pid, p.Planificador.BlockQueue)
+	p.mutexBlockQueue.Unlock()
+	if !removido {
+		return false
+	}
+
+	// Si tenía una suspensión por tiempo programada, ya no corresponde
+	p.Temporizadores.Cancelar(pid)
+
+	p.mutexSuspBlockQueue.Lock()
+	p.Planificador.SuspBlockQueue = append(p.Planificador.SuspBlockQueue, proceso)
+	p.mutexSuspBlockQueue.Unlock()
+
+	// Actualizar métricas
+	tiempo := proceso.PCB.MetricasTiempo[internal.EstadoBloqueado]
+	tiempo.TiempoAcumulado += clock.Since(tiempo.TiempoInicio)
+
+	if proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado] == nil {
+		proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado] = &internal.EstadoTiempo{}
+	}
+	proceso.PCB.MetricasTiempo[internal.EstadoSuspBloqueado].TiempoInicio = clock.Now()
+	proceso.PCB.MetricasEstado[internal.EstadoSuspBloqueado]++
+
+	//Log obligatorio: Cambio de estado
+	// "## (<PID>) Pasa del estado <ESTADO_ANTERIOR> al estado <ESTADO_ACTUAL>"
+	p.registrarCambioEstado(pid, internal.EstadoBloqueado, internal.EstadoSuspBloqueado, "")
+	metricaSuspensiones.Inc(p.MedianoPlazoConfig.Politica)
+
+	// Notificar a memoria que debe swappear, antes de intentar admitir otro proceso en su lugar
+	p.avisarAMemoriaSwap(proceso)
+
+	return true
+}
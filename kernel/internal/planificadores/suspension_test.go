@@ -0,0 +1,60 @@
+package planificadores
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+)
+
+func TestSuspension_ElegirVictima(t *testing.T) {
+	inicio := time.Unix(0, 0)
+	bloqueado := func(pid, tamanio, prioridad int, desde time.Duration) *internal.Proceso {
+		return &internal.Proceso{PCB: &internal.PCB{
+			PID:          pid,
+			TamanioBytes: tamanio,
+			Prioridad:    prioridad,
+			MetricasTiempo: map[internal.Estado]*internal.EstadoTiempo{
+				internal.EstadoBloqueado: {TiempoInicio: inicio.Add(desde)},
+			},
+		}}
+	}
+
+	// En orden de llegada a BLOCKED: PID, tamaño, prioridad e instante en que se bloqueó
+	bloqueados := []*internal.Proceso{
+		bloqueado(1, 64, 1, 300*time.Millisecond),
+		bloqueado(2, 256, 3, 200*time.Millisecond),
+		bloqueado(3, 128, 3, 100*time.Millisecond),
+		bloqueado(4, 256, 0, 400*time.Millisecond),
+	}
+
+	tests := []struct {
+		politica string
+		victima  int
+	}{
+		{"LARGEST", 2},         // Empatan 2 y 4 con 256 bytes, se elige el primero
+		{"LONGEST_BLOCKED", 3}, // Bloqueado desde los 100ms
+		{"LOWEST_PRIORITY", 3}, // Empatan 2 y 3 con prioridad 3, se elige el bloqueado hace más
+		{"TIMER", -1},          // No suspende por falta de memoria
+	}
+
+	p := &Service{
+		Log:                slog.New(slog.NewTextHandler(io.Discard, nil)),
+		MedianoPlazoConfig: &MedianoPlazoConfig{},
+	}
+	for _, tt := range tests {
+		t.Run(tt.politica, func(t *testing.T) {
+			indice := p.nuevaPoliticaSuspension(tt.politica).ElegirVictima(bloqueados)
+
+			victima := -1
+			if indice >= 0 {
+				victima = bloqueados[indice].PCB.PID
+			}
+			if victima != tt.victima {
+				t.Errorf("víctima = %d, se esperaba %d", victima, tt.victima)
+			}
+		})
+	}
+}
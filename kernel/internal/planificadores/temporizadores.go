@@ -0,0 +1,189 @@
+package planificadores
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+)
+
+// Temporizadores es un servicio de temporizadores atendido por una sola goroutine, que duerme hasta el vencimiento
+// más próximo de un min-heap. Cada clave (por ejemplo, un PID) tiene a lo sumo un temporizador pendiente, y cada vez
+// que se programa o se cancela uno aumenta la generación de la clave: así la acción de un temporizador reemplazado
+// puede detectar que ya no corresponde, aunque haya vencido al mismo tiempo.
+// Las acciones se ejecutan en la goroutine de los temporizadores, por lo que no deben quedarse esperando a otro
+// temporizador.
+type Temporizadores struct {
+	mutex        sync.Mutex
+	cola         colaTemporizadores
+	pendientes   map[int]*temporizador // Temporizador pendiente por clave
+	generaciones map[int]uint64        // Última generación por clave
+	secuencia    uint64                // Orden de programación, para desempatar vencimientos iguales
+	despertar    chan struct{}         // Avisa a la goroutine que cambió el vencimiento más próximo
+}
+
+type temporizador struct {
+	clave      int
+	generacion uint64
+	secuencia  uint64
+	vence      time.Time
+	accion     func(generacion uint64)
+	indice     int // Posición en el heap, la mantiene colaTemporizadores
+}
+
+func NewTemporizadores() *Temporizadores {
+	t := &Temporizadores{
+		cola:         make(colaTemporizadores, 0),
+		pendientes:   make(map[int]*temporizador),
+		generaciones: make(map[int]uint64),
+		despertar:    make(chan struct{}, 1),
+	}
+	go t.atender()
+
+	return t
+}
+
+// Programar programa la acción de la clave para dentro de d, reemplazando el temporizador pendiente si lo había.
+// Devuelve la generación del nuevo temporizador, que también recibe la acción al ejecutarse.
+func (t *Temporizadores) Programar(clave int, d time.Duration, accion func(generacion uint64)) uint64 {
+	t.mutex.Lock()
+	t.quitar(clave)
+	t.generaciones[clave]++
+	t.secuencia++
+
+	nuevo := &temporizador{
+		clave:      clave,
+		generacion: t.generaciones[clave],
+		secuencia:  t.secuencia,
+		vence:      clock.Now().Add(d),
+		accion:     accion,
+	}
+	heap.Push(&t.cola, nuevo)
+	t.pendientes[clave] = nuevo
+	t.mutex.Unlock()
+
+	t.avisar()
+	return nuevo.generacion
+}
+
+// Cancelar descarta el temporizador pendiente de la clave e invalida su generación, incluso si ya venció y su acción
+// todavía no verificó si sigue vigente. Devuelve false si no había un temporizador pendiente.
+func (t *Temporizadores) Cancelar(clave int) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.generaciones[clave]; !ok {
+		return false
+	}
+	t.generaciones[clave]++
+
+	return t.quitar(clave)
+}
+
+// Vigente informa si la generación dada sigue siendo la última de la clave, es decir, si desde que se programó su
+// temporizador no se programó otro ni se lo canceló
+func (t *Temporizadores) Vigente(clave int, generacion uint64) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.generaciones[clave] == generacion
+}
+
+// Pendientes devuelve la cantidad de temporizadores que todavía no vencieron
+func (t *Temporizadores) Pendientes() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return len(t.cola)
+}
+
+// quitar saca del heap el temporizador pendiente de la clave, si lo hay
+// IMPORTANTE: Se llama con el mutex bloqueado
+func (t *Temporizadores) quitar(clave int) bool {
+	pendiente, ok := t.pendientes[clave]
+	if !ok {
+		return false
+	}
+
+	heap.Remove(&t.cola, pendiente.indice)
+	delete(t.pendientes, clave)
+	return true
+}
+
+func (t *Temporizadores) avisar() {
+	select {
+	case t.despertar <- struct{}{}:
+	default:
+	}
+}
+
+// atender es la goroutine del servicio: ejecuta los temporizadores vencidos en orden y duerme hasta el próximo
+// vencimiento o hasta que se programe uno anterior
+func (t *Temporizadores) atender() {
+	for {
+		t.mutex.Lock()
+		if len(t.cola) == 0 {
+			t.mutex.Unlock()
+			<-t.despertar
+			continue
+		}
+
+		proximo := t.cola[0]
+		ahora := clock.Now()
+		espera := proximo.vence.Sub(ahora)
+		if espera <= 0 {
+			heap.Pop(&t.cola)
+			delete(t.pendientes, proximo.clave)
+			t.mutex.Unlock()
+
+			proximo.accion(proximo.generacion)
+			continue
+		}
+		t.mutex.Unlock()
+
+		timer := clock.AfterFunc(espera, t.avisar)
+
+		// Con el reloj virtual, si el tiempo avanzó antes de programar la espera, esta quedó corrida: se recalcula
+		if clock.EsVirtual() && clock.Now().After(ahora) {
+			t.avisar()
+		}
+		<-t.despertar
+		timer.Stop()
+	}
+}
+
+// colaTemporizadores implementa heap.Interface ordenando por vencimiento y, a igual vencimiento, por orden de
+// programación
+type colaTemporizadores []*temporizador
+
+func (c colaTemporizadores) Len() int { return len(c) }
+
+func (c colaTemporizadores) Less(i, j int) bool {
+	if c[i].vence.Equal(c[j].vence) {
+		return c[i].secuencia < c[j].secuencia
+	}
+	return c[i].vence.Before(c[j].vence)
+}
+
+func (c colaTemporizadores) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+	c[i].indice = i
+	c[j].indice = j
+}
+
+func (c *colaTemporizadores) Push(x any) {
+	nuevo := x.(*temporizador)
+	nuevo.indice = len(*c)
+	*c = append(*c, nuevo)
+}
+
+func (c *colaTemporizadores) Pop() any {
+	anterior := *c
+	n := len(anterior)
+	ultimo := anterior[n-1]
+	anterior[n-1] = nil
+	ultimo.indice = -1
+	*c = anterior[:n-1]
+	return ultimo
+}
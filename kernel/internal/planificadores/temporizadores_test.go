@@ -0,0 +1,98 @@
+package planificadores
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+)
+
+func TestTemporizadores_ReemplazoYCancelacion(t *testing.T) {
+	inicio := time.Unix(0, 0)
+	reloj := clock.NewVirtual(inicio)
+	clock.Usar(reloj)
+	defer clock.Usar(clock.Real{})
+
+	temporizadores := NewTemporizadores()
+	vencidos := make(chan int, 10)
+	vencer := func(clave int) func(uint64) {
+		return func(generacion uint64) {
+			if temporizadores.Vigente(clave, generacion) {
+				vencidos <- clave
+			}
+		}
+	}
+
+	// esperarVencido adelanta el reloj hasta el instante dado y espera a que la goroutine ejecute una acción
+	esperarVencido := func(hasta time.Duration) int {
+		t.Helper()
+		reloj.AvanzarHasta(inicio.Add(hasta))
+		select {
+		case clave := <-vencidos:
+			return clave
+		case <-time.After(time.Second):
+			t.Fatalf("ningún temporizador venció al llegar a %v", hasta)
+			return 0
+		}
+	}
+
+	temporizadores.Programar(1, 300*time.Millisecond, vencer(1))
+	temporizadores.Programar(2, 100*time.Millisecond, vencer(2))
+	temporizadores.Programar(3, 200*time.Millisecond, vencer(3))
+
+	// El PID 1 se desbloquea y se vuelve a bloquear: solo cuenta el segundo bloqueo
+	viejo := temporizadores.Programar(1, 500*time.Millisecond, vencer(1))
+	temporizadores.Cancelar(1)
+	temporizadores.Programar(1, 400*time.Millisecond, vencer(1))
+	if temporizadores.Vigente(1, viejo) {
+		t.Fatalf("la generación de un bloqueo anterior sigue vigente")
+	}
+	temporizadores.Cancelar(3)
+
+	if n := temporizadores.Pendientes(); n != 2 {
+		t.Fatalf("Pendientes() = %d, se esperaba 2", n)
+	}
+
+	if clave := esperarVencido(150 * time.Millisecond); clave != 2 {
+		t.Fatalf("venció %d a los 150ms, se esperaba 2", clave)
+	}
+	if n := temporizadores.Pendientes(); n != 1 {
+		t.Fatalf("Pendientes() = %d a los 150ms, se esperaba 1", n)
+	}
+	if clave := esperarVencido(time.Second); clave != 1 {
+		t.Fatalf("venció %d, se esperaba 1", clave)
+	}
+	if n := temporizadores.Pendientes(); n != 0 {
+		t.Errorf("Pendientes() = %d luego de vencer todos", n)
+	}
+}
+
+func TestTemporizadores_GeneracionVencida(t *testing.T) {
+	reloj := clock.NewVirtual(time.Unix(0, 0))
+	clock.Usar(reloj)
+	defer clock.Usar(clock.Real{})
+
+	temporizadores := NewTemporizadores()
+	var ejecutadas []uint64
+	accion := func(generacion uint64) {
+		if temporizadores.Vigente(7, generacion) {
+			ejecutadas = append(ejecutadas, generacion)
+		}
+	}
+
+	// La acción del primer bloqueo ya salió del heap cuando el proceso se vuelve a bloquear: al ejecutarse tarde no
+	// debe suspenderlo, porque corresponde a un bloqueo que ya terminó
+	viejo := temporizadores.Programar(7, time.Hour, accion)
+	nuevo := temporizadores.Programar(7, time.Hour, accion)
+	accion(viejo)
+	if len(ejecutadas) != 0 {
+		t.Fatalf("se ejecutó la generación %d, reemplazada por %d", viejo, nuevo)
+	}
+
+	// Cancelar también invalida la generación, aunque el temporizador ya haya vencido
+	temporizadores.Cancelar(7)
+	accion(nuevo)
+	if len(ejecutadas) != 0 {
+		t.Fatalf("se ejecutó la generación %d luego de cancelarla", nuevo)
+	}
+}
@@ -0,0 +1,301 @@
+package planificadores
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/internal"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+)
+
+const (
+	RecursoDumpMemory = "DUMP_MEMORY" // Recurso de los bloqueos por DUMP_MEMORY, que no usan un dispositivo IO
+
+	anchoGanttASCII = 100 // Columnas de la escala de tiempo del diagrama ASCII
+	anchoGanttSVG   = 1000
+	altoFilaSVG     = 30
+	margenSVG       = 120
+)
+
+// EventoTimeline es un cambio de estado de un proceso. Recurso es la CPU en la que pasa a ejecutar (al pasar a
+// EXEC) o el dispositivo por el que se bloquea (al pasar a BLOCKED).
+type EventoTimeline struct {
+	Instante time.Time       `json:"instante"`
+	PID      int             `json:"pid"`
+	Desde    internal.Estado `json:"desde,omitempty"`
+	Hacia    internal.Estado `json:"hacia"`
+	Recurso  string          `json:"recurso,omitempty"`
+}
+
+// Timeline guarda todos los cambios de estado de los procesos, en orden, para poder exportarlos como JSON o como
+// diagrama de Gantt
+type Timeline struct {
+	mutex   sync.Mutex
+	eventos []EventoTimeline
+}
+
+// IntervaloGantt es el tiempo que un proceso ocupó una CPU o un dispositivo IO
+type IntervaloGantt struct {
+	Recurso string
+	PID     int
+	Desde   time.Time
+	Hasta   time.Time
+}
+
+func NewTimeline() *Timeline {
+	return &Timeline{eventos: make([]EventoTimeline, 0)}
+}
+
+// Registrar agrega un cambio de estado al final del timeline
+func (t *Timeline) Registrar(pid int, desde, hacia internal.Estado, recurso string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.eventos = append(t.eventos, EventoTimeline{
+		Instante: clock.Now(),
+		PID:      pid,
+		Desde:    desde,
+		Hacia:    hacia,
+		Recurso:  recurso,
+	})
+}
+
+// Eventos devuelve una copia de los cambios de estado registrados
+func (t *Timeline) Eventos() []EventoTimeline {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	eventos := make([]EventoTimeline, len(t.eventos))
+	copy(eventos, t.eventos)
+	return eventos
+}
+
+// UltimoEvento devuelve el último cambio de estado registrado del proceso
+func (t *Timeline) UltimoEvento(pid int) (EventoTimeline, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for i := len(t.eventos) - 1; i >= 0; i-- {
+		if t.eventos[i].PID == pid {
+			return t.eventos[i], true
+		}
+	}
+
+	return EventoTimeline{}, false
+}
+
+// Intervalos arma los intervalos de ocupación de cada CPU y dispositivo IO a partir de los cambios de estado. Un
+// intervalo de CPU va desde que el proceso pasa a EXEC hasta que sale de EXEC, y uno de IO desde que se bloquea hasta
+// que sale de BLOCKED o SUSP.BLOCKED. Los intervalos que siguen abiertos terminan en el instante hasta.
+func (t *Timeline) Intervalos(hasta time.Time) []IntervaloGantt {
+	type abierto struct {
+		recurso string
+		desde   time.Time
+	}
+
+	var (
+		intervalos = make([]IntervaloGantt, 0)
+		abiertos   = make(map[int]abierto)
+	)
+
+	for _, evento := range t.Eventos() {
+		if a, ok := abiertos[evento.PID]; ok && evento.Hacia != internal.EstadoSuspBloqueado {
+			intervalos = append(intervalos, IntervaloGantt{Recurso: a.recurso, PID: evento.PID, Desde: a.desde, Hasta: evento.Instante})
+			delete(abiertos, evento.PID)
+		}
+
+		if (evento.Hacia == internal.EstadoExec || evento.Hacia == internal.EstadoBloqueado) && evento.Recurso != "" {
+			abiertos[evento.PID] = abierto{recurso: evento.Recurso, desde: evento.Instante}
+		}
+	}
+
+	for pid, a := range abiertos {
+		intervalos = append(intervalos, IntervaloGantt{Recurso: a.recurso, PID: pid, Desde: a.desde, Hasta: hasta})
+	}
+
+	sort.SliceStable(intervalos, func(i, j int) bool {
+		if intervalos[i].Recurso != intervalos[j].Recurso {
+			return intervalos[i].Recurso < intervalos[j].Recurso
+		}
+		return intervalos[i].Desde.Before(intervalos[j].Desde)
+	})
+
+	return intervalos
+}
+
+// JSON devuelve los cambios de estado registrados en formato JSON
+func (t *Timeline) JSON() ([]byte, error) {
+	return json.MarshalIndent(t.Eventos(), "", "  ")
+}
+
+// GanttASCII devuelve un diagrama de Gantt en texto con una fila por CPU y por dispositivo IO. Cada columna
+// representa una fracción del tiempo total y muestra el PID (en base 36) del proceso que ocupaba el recurso.
+func (t *Timeline) GanttASCII() string {
+	inicio, fin, recursos, intervalos := t.datosGantt()
+	if len(recursos) == 0 {
+		return "Sin eventos de CPU ni IO\n"
+	}
+
+	total := fin.Sub(inicio)
+	if total <= 0 {
+		total = time.Millisecond
+	}
+
+	anchoNombre := 0
+	for _, recurso := range recursos {
+		anchoNombre = max(anchoNombre, len(recurso))
+	}
+
+	filas := make(map[string][]byte, len(recursos))
+	for _, recurso := range recursos {
+		filas[recurso] = []byte(strings.Repeat(".", anchoGanttASCII))
+	}
+
+	for _, intervalo := range intervalos {
+		desde := int(intervalo.Desde.Sub(inicio) * anchoGanttASCII / total)
+		hasta := int(intervalo.Hasta.Sub(inicio) * anchoGanttASCII / total)
+		if hasta <= desde {
+			hasta = desde + 1
+		}
+		for col := desde; col < hasta && col < anchoGanttASCII; col++ {
+			filas[intervalo.Recurso][col] = strconv.FormatInt(int64(intervalo.PID%36), 36)[0]
+		}
+	}
+
+	var sb strings.Builder
+	for _, recurso := range recursos {
+		sb.WriteString(fmt.Sprintf("%-*s |%s|\n", anchoNombre, recurso, filas[recurso]))
+	}
+	sb.WriteString(fmt.Sprintf("%-*s  0ms%*dms\n", anchoNombre, "", anchoGanttASCII-3, total.Milliseconds()))
+
+	return sb.String()
+}
+
+// GanttSVG devuelve un diagrama de Gantt en SVG con una fila por CPU y por dispositivo IO
+func (t *Timeline) GanttSVG() string {
+	inicio, fin, recursos, intervalos := t.datosGantt()
+
+	total := fin.Sub(inicio)
+	if total <= 0 {
+		total = time.Millisecond
+	}
+
+	fila := make(map[string]int, len(recursos))
+	for i, recurso := range recursos {
+		fila[recurso] = i
+	}
+
+	alto := (len(recursos)+1)*altoFilaSVG + 10
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="monospace" font-size="12">`+"\n",
+		anchoGanttSVG+margenSVG+20, alto))
+
+	for i, recurso := range recursos {
+		y := i*altoFilaSVG + 10
+		sb.WriteString(fmt.Sprintf(`<text x="5" y="%d">%s</text>`+"\n", y+altoFilaSVG/2+4, html.EscapeString(recurso)))
+		sb.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#ccc"/>`+"\n",
+			margenSVG, y+altoFilaSVG, margenSVG+anchoGanttSVG, y+altoFilaSVG))
+	}
+
+	for _, intervalo := range intervalos {
+		x := margenSVG + int(intervalo.Desde.Sub(inicio)*anchoGanttSVG/total)
+		ancho := max(int(intervalo.Hasta.Sub(intervalo.Desde)*anchoGanttSVG/total), 1)
+		y := fila[intervalo.Recurso]*altoFilaSVG + 12
+		color := fmt.Sprintf("hsl(%d,60%%,65%%)", (intervalo.PID*47)%360)
+
+		sb.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="#333"><title>PID %d: %dms - %dms</title></rect>`+"\n",
+			x, y, ancho, altoFilaSVG-4, color, intervalo.PID,
+			intervalo.Desde.Sub(inicio).Milliseconds(), intervalo.Hasta.Sub(inicio).Milliseconds()))
+		sb.WriteString(fmt.Sprintf(`<text x="%d" y="%d">%d</text>`+"\n", x+2, y+altoFilaSVG/2+2, intervalo.PID))
+	}
+
+	yEscala := len(recursos)*altoFilaSVG + 25
+	sb.WriteString(fmt.Sprintf(`<text x="%d" y="%d">0ms</text>`+"\n", margenSVG, yEscala))
+	sb.WriteString(fmt.Sprintf(`<text x="%d" y="%d" text-anchor="end">%dms</text>`+"\n",
+		margenSVG+anchoGanttSVG, yEscala, total.Milliseconds()))
+	sb.WriteString("</svg>\n")
+
+	return sb.String()
+}
+
+// Exportar escribe el timeline en el directorio dado como timeline.json, gantt.txt y gantt.svg
+func (t *Timeline) Exportar(directorio string) error {
+	if err := os.MkdirAll(directorio, 0755); err != nil {
+		return fmt.Errorf("error creando directorio del timeline: %w", err)
+	}
+
+	contenidoJSON, err := t.JSON()
+	if err != nil {
+		return fmt.Errorf("error serializando el timeline: %w", err)
+	}
+
+	archivos := map[string][]byte{
+		"timeline.json": contenidoJSON,
+		"gantt.txt":     []byte(t.GanttASCII()),
+		"gantt.svg":     []byte(t.GanttSVG()),
+	}
+	for nombre, contenido := range archivos {
+		if err = os.WriteFile(filepath.Join(directorio, nombre), contenido, 0644); err != nil {
+			return fmt.Errorf("error escribiendo %s: %w", nombre, err)
+		}
+	}
+
+	return nil
+}
+
+// datosGantt devuelve el rango de tiempo del timeline, los recursos ordenados (primero las CPUs, luego las IOs) y los
+// intervalos de ocupación
+func (t *Timeline) datosGantt() (time.Time, time.Time, []string, []IntervaloGantt) {
+	ahora := clock.Now()
+	eventos := t.Eventos()
+	intervalos := t.Intervalos(ahora)
+
+	inicio, fin := ahora, ahora
+	if len(eventos) > 0 {
+		inicio = eventos[0].Instante
+	}
+
+	esCPU := make(map[string]bool)
+	for _, evento := range eventos {
+		if evento.Hacia == internal.EstadoExec && evento.Recurso != "" {
+			esCPU[evento.Recurso] = true
+		}
+	}
+
+	vistos := make(map[string]bool)
+	recursos := make([]string, 0)
+	for _, intervalo := range intervalos {
+		if !vistos[intervalo.Recurso] {
+			vistos[intervalo.Recurso] = true
+			recursos = append(recursos, intervalo.Recurso)
+		}
+	}
+	sort.SliceStable(recursos, func(i, j int) bool {
+		if esCPU[recursos[i]] != esCPU[recursos[j]] {
+			return esCPU[recursos[i]]
+		}
+		return recursos[i] < recursos[j]
+	})
+
+	return inicio, fin, recursos, intervalos
+}
+
+// registrarCambioEstado escribe el log obligatorio de cambio de estado y lo agrega al timeline y a las métricas
+func (p *Service) registrarCambioEstado(pid int, desde, hacia internal.Estado, recurso string) {
+	p.Log.Info(fmt.Sprintf("## (%d) Pasa del estado %s al estado %s", pid, desde, hacia))
+	p.Timeline.Registrar(pid, desde, hacia, recurso)
+	metricaTransiciones.Inc(string(desde), string(hacia))
+}
+
+// RegistrarCreacion agrega al timeline la creación de un proceso en NEW
+func (p *Service) RegistrarCreacion(pid int) {
+	p.Timeline.Registrar(pid, "", internal.EstadoNew, "")
+}
@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/cmd/api"
+	"github.com/sisoputnfrba/tp-golang/utils/metricas"
 )
 
 const (
@@ -15,7 +19,7 @@ const (
 func main() {
 	// El primer argumento es el archivo de configuración y el segundo es el tamaño del proceso
 	if len(os.Args) < 4 {
-		fmt.Println("Faltan argumentos. Uso: go run kernel.go <archivo_nombre> <tamanio_proceso> <config_id>")
+		fmt.Println("Faltan argumentos. Uso: go run kernel.go <archivo_nombre> <tamanio_proceso> <config_id> [prioridad] [tickets]")
 		os.Exit(1)
 	}
 
@@ -23,6 +27,27 @@ func main() {
 	tamanioProceso := os.Args[2] // Tamaño en bytes
 	configID := os.Args[3]       // ID de configuración
 
+	// La prioridad del proceso inicial es opcional, por defecto es 0 (la mayor)
+	var prioridad int
+	if len(os.Args) > 4 {
+		var err error
+		if prioridad, err = strconv.Atoi(os.Args[4]); err != nil || prioridad < 0 {
+			fmt.Println("La prioridad debe ser un número entero no negativo")
+			os.Exit(1)
+		}
+	}
+
+	// Los tickets del proceso inicial (LOTTERY, STRIDE) también son opcionales, por defecto se usan los de la
+	// configuración
+	var tickets int
+	if len(os.Args) > 5 {
+		var err error
+		if tickets, err = strconv.Atoi(os.Args[5]); err != nil || tickets <= 0 {
+			fmt.Println("Los tickets deben ser un número entero positivo")
+			os.Exit(1)
+		}
+	}
+
 	configFile := configFilePath + configID + ".json"
 
 	h := api.NewHandler(configFile)
@@ -34,9 +59,43 @@ func main() {
 	mux.HandleFunc("/io/peticion-finalizada", h.TerminoPeticionIO) // IO --> KERNEL (usleep)
 
 	mux.HandleFunc("/cpu/proceso", h.RespuestaProcesoCPU) //CPU --> Kernel (Recibe respuesta del proceso de la CPU) PROCESO
+	mux.HandleFunc("/cpu/contexto", h.RecibirContexto)    //CPU --> Kernel (Devuelve el contexto al terminar la ráfaga)
+	mux.HandleFunc("/cpu/avance", h.AvanceRafaga)         //CPU --> Kernel (Informa cuánto lleva la ráfaga, con el reloj virtual)
+
+	// Consultas de solo lectura del estado del sistema
+	mux.HandleFunc("GET /procesos", h.ConsultarProcesos)
+	mux.HandleFunc("GET /procesos/{pid}", h.ConsultarProceso)
+	mux.HandleFunc("GET /colas", h.ConsultarColas)
+	mux.HandleFunc("GET /cpus", h.ConsultarCPUs)
+	mux.HandleFunc("GET /ios", h.ConsultarIOs)
+
+	// Control administrativo de procesos
+	mux.HandleFunc("POST /procesos/{pid}/finalizar", h.FinalizarProcesoAdmin)
+	mux.HandleFunc("POST /procesos/{pid}/suspender", h.SuspenderProcesoAdmin)
+	mux.HandleFunc("POST /procesos/{pid}/reanudar", h.ReanudarProcesoAdmin)
+	mux.HandleFunc("GET /multiprogramacion", h.ConsultarMultiprogramacion)
+	mux.HandleFunc("PUT /multiprogramacion", h.CambiarMultiprogramacion)
+
+	mux.HandleFunc("/timeline", h.Timeline) // Consulta del timeline: ?formato=json|svg|ascii
+	mux.Handle("GET /metrics", metricas.Handler())
+
+	consola := api.NuevaConsola()
+
+	// Al terminar el kernel (Ctrl+C) se restaura la terminal y se exporta el timeline, si hay un directorio configurado
+	senales := make(chan os.Signal, 1)
+	signal.Notify(senales, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-senales
+		consola.Restaurar()
+		h.ExportarTimeline()
+		os.Exit(0)
+	}()
 
 	// Kernel --> Memoria
-	h.EjecutarPlanificadores(archivoNombre, tamanioProceso)
+	h.EjecutarPlanificadores(archivoNombre, tamanioProceso, prioridad, tickets)
+
+	// Consola interactiva: inicia y detiene la planificación, crea y finaliza procesos
+	go h.EjecutarConsola(consola)
 
 	err := http.ListenAndServe(fmt.Sprintf(":%d", h.Config.PortKernel), mux)
 	if err != nil {
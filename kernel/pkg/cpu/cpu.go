@@ -22,9 +22,10 @@ type Cpu struct {
 }
 
 type ProcesoCpu struct {
-	PID    int    `json:"pid"`
-	PC     int    `json:"pc"`
-	Motivo string `json:"motivo,omitempty"`
+	PID     int    `json:"pid"`
+	PC      int    `json:"pc"`
+	Tamanio int    `json:"tamanio"` // Tamaño del proceso, para que la MMU valide sus direcciones lógicas
+	Motivo  string `json:"motivo,omitempty"`
 }
 
 type Interrupcion struct {
@@ -35,7 +36,7 @@ type Interrupcion struct {
 
 func NewCpu(ip string, puerto int, id string, logger *slog.Logger) *Cpu {
 	httpClient := &http.Client{
-		Timeout: 2 * time.Minute,
+		Timeout: 10 * time.Second, // El despacho ya no espera a que termine la ráfaga
 	}
 	return &Cpu{
 		IP:     ip,
@@ -50,13 +51,15 @@ func NewCpu(ip string, puerto int, id string, logger *slog.Logger) *Cpu {
 	}
 }
 
-func (c *Cpu) DispatchProcess() (int, string) {
+// DispatchProcess envía el proceso asignado a la CPU. La CPU responde apenas lo recibe y devuelve el contexto cuando
+// termina la ráfaga.
+func (c *Cpu) DispatchProcess() error {
 	body, err := json.Marshal(*c.Proceso)
 	if err != nil {
 		c.Log.Error("Error al serializar el proceso",
 			log.ErrAttr(err),
 		)
-		return c.Proceso.PC, ""
+		return err
 	}
 
 	url := fmt.Sprintf("http://%s:%d/kernel/procesos", c.IP, c.Puerto)
@@ -67,21 +70,20 @@ func (c *Cpu) DispatchProcess() (int, string) {
 			log.StringAttr("ip", c.IP),
 			log.IntAttr("puerto", c.Puerto),
 		)
+		return err
 	}
+	defer resp.Body.Close()
 
-	newResponse := &ProcesoCpu{}
-	if resp != nil {
-		c.Log.Debug("Respuesta del servidor",
-			log.StringAttr("status", resp.Status),
-			log.StringAttr("body", string(body)),
-		)
+	c.Log.Debug("Respuesta del servidor",
+		log.StringAttr("status", resp.Status),
+		log.StringAttr("body", string(body)),
+	)
 
-		_ = json.NewDecoder(resp.Body).Decode(newResponse)
-		c.Proceso.PID = newResponse.PID
-		c.Proceso.PC = newResponse.PC
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("la CPU %s rechazó el proceso con status %d", c.ID, resp.StatusCode)
 	}
 
-	return c.Proceso.PC, newResponse.Motivo
+	return nil
 }
 
 func (c *Cpu) EnviarInterrupcion(tipo string, esEnmascarable bool) bool {
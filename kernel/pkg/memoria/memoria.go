@@ -1,9 +1,15 @@
 package memoria
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sisoputnfrba/tp-golang/utils/log"
@@ -173,3 +179,194 @@ func (m *Memoria) SwapProceso(pid int) error {
 
 	return nil
 }
+
+// MarcosLibres consulta a memoria cuántos marcos libres tiene y el tamaño de página, sin reservar nada
+func (m *Memoria) MarcosLibres() (marcosLibres, tamanioPagina int, err error) {
+	url := fmt.Sprintf("http://%s:%d/kernel/marcos-libres", m.IP, m.Puerto)
+
+	var respuesta struct {
+		MarcosLibres  int `json:"marcos_libres"`
+		TamanioPagina int `json:"tamanio_pagina"`
+	}
+	if err = m.consultarJSON(url, &respuesta); err != nil {
+		return 0, 0, err
+	}
+
+	return respuesta.MarcosLibres, respuesta.TamanioPagina, nil
+}
+
+// CantidadInstrucciones consulta a memoria cuántas instrucciones tiene un archivo de pseudocódigo
+func (m *Memoria) CantidadInstrucciones(archivo string) (int, error) {
+	url := fmt.Sprintf("http://%s:%d/kernel/cantidad-instrucciones?archivo=%s", m.IP, m.Puerto, neturl.QueryEscape(archivo))
+
+	var respuesta struct {
+		Cantidad int `json:"cantidad"`
+	}
+	if err := m.consultarJSON(url, &respuesta); err != nil {
+		return 0, err
+	}
+
+	return respuesta.Cantidad, nil
+}
+
+// MetricasProceso son las métricas que memoria registró de un proceso hasta destruirlo
+type MetricasProceso struct {
+	AccesosTablaDePaginas    int `json:"accesos_tabla_de_paginas"`
+	InstruccionesSolicitadas int `json:"instrucciones_solicitadas"`
+	BajadasAlSwap            int `json:"bajadas_a_swap"`
+	SubidasAMemPpal          int `json:"subidas_a_memoria_principal"`
+	LecturasDeMemoria        int `json:"lecturas_de_memoria"`
+	EscriturasDeMemoria      int `json:"escrituras_de_memoria"`
+}
+
+// MetricasProceso consulta a memoria las métricas de un proceso ya finalizado
+func (m *Memoria) MetricasProceso(pid int) (*MetricasProceso, error) {
+	url := fmt.Sprintf("http://%s:%d/kernel/metricas-proceso?pid=%d", m.IP, m.Puerto, pid)
+
+	var metricas MetricasProceso
+	if err := m.consultarJSON(url, &metricas); err != nil {
+		return nil, err
+	}
+
+	return &metricas, nil
+}
+
+// paginacion es la configuración de paginación de memoria, necesaria para traducir direcciones lógicas
+type paginacion struct {
+	TamanioPagina int `json:"page_size"`
+	Entradas      int `json:"entries_per_page"`
+	Niveles       int `json:"number_of_levels"`
+}
+
+// escritura es el cuerpo de una escritura en un marco, el mismo que envía la CPU
+type escritura struct {
+	PID            string `json:"pid"`
+	Frame          int    `json:"frame"`
+	Offset         int    `json:"offset"`
+	ValorAEscribir string `json:"valor_a_escribir"`
+}
+
+// EscribirEnProceso escribe datos a partir de una dirección lógica del proceso, por el mismo camino que la CPU: busca
+// el marco de cada página en la tabla de páginas del proceso y escribe en él. Si los datos no entran en la página, se
+// continúa en la siguiente.
+// IMPORTANTE: El proceso tiene que estar en memoria principal y fuera de la CPU, para no pisar su caché
+func (m *Memoria) EscribirEnProceso(pid, direccionLogica int, datos string) error {
+	if direccionLogica < 0 {
+		return fmt.Errorf("dirección lógica inválida: %d", direccionLogica)
+	}
+
+	var config paginacion
+	if err := m.consultarJSON(fmt.Sprintf("http://%s:%d/cpu/page-size-y-entries", m.IP, m.Puerto), &config); err != nil {
+		return err
+	}
+	if config.TamanioPagina <= 0 {
+		return fmt.Errorf("tamaño de página inválido: %d", config.TamanioPagina)
+	}
+
+	for escrito := 0; escrito < len(datos); {
+		direccion := direccionLogica + escrito
+		pagina, offset := direccion/config.TamanioPagina, direccion%config.TamanioPagina
+		fin := min(len(datos), escrito+config.TamanioPagina-offset)
+
+		var marco struct {
+			Frame int `json:"frame"`
+		}
+		url := fmt.Sprintf("http://%s:%d/cpu/pagina-a-frame?pid=%d&entradas-nivel=%s",
+			m.IP, m.Puerto, pid, config.entradasPorNivel(pagina))
+		if err := m.consultarJSON(url, &marco); err != nil {
+			return err
+		}
+		if marco.Frame < 0 {
+			return fmt.Errorf("la página %d del proceso %d no tiene marco asignado", pagina, pid)
+		}
+
+		if err := m.escribirEnMarco(escritura{
+			PID:            strconv.Itoa(pid),
+			Frame:          marco.Frame,
+			Offset:         offset,
+			ValorAEscribir: datos[escrito:fin],
+		}); err != nil {
+			return err
+		}
+		escrito = fin
+	}
+
+	m.Log.Debug("Escritura en el espacio de usuario del proceso",
+		log.IntAttr("pid", pid),
+		log.IntAttr("direccion_logica", direccionLogica),
+		log.IntAttr("tamanio", len(datos)),
+	)
+
+	return nil
+}
+
+// entradasPorNivel calcula la entrada de cada nivel de la tabla de páginas para la página, con el formato que espera
+// memoria ("1-0-3"). Es el mismo cálculo que hace la MMU de la CPU.
+func (c paginacion) entradasPorNivel(pagina int) string {
+	if c.Entradas <= 0 || c.Niveles <= 0 {
+		return strconv.Itoa(pagina)
+	}
+
+	entradas := make([]string, c.Niveles)
+	for nivel := c.Niveles - 1; nivel >= 0; nivel-- {
+		entradas[nivel] = strconv.Itoa(pagina % c.Entradas)
+		pagina /= c.Entradas
+	}
+
+	return strings.Join(entradas, "-")
+}
+
+func (m *Memoria) escribirEnMarco(peticion escritura) error {
+	body, err := json.Marshal(peticion)
+	if err != nil {
+		return fmt.Errorf("error al serializar la escritura: %w", err)
+	}
+
+	resp, err := m.httpClient.Post(fmt.Sprintf("http://%s:%d/cpu/escritura", m.IP, m.Puerto),
+		"application/json", bytes.NewBuffer(body))
+	if err != nil {
+		m.Log.Error("Error al escribir en memoria",
+			log.ErrAttr(err),
+			log.StringAttr("ip", m.IP),
+			log.IntAttr("puerto", m.Puerto),
+		)
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respuesta, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(respuesta) != "OK" {
+		return fmt.Errorf("memoria respondió con status %d a la escritura: %s", resp.StatusCode, respuesta)
+	}
+
+	return nil
+}
+
+// consultarJSON hace un GET a memoria y decodifica la respuesta JSON
+func (m *Memoria) consultarJSON(url string, respuesta any) error {
+	resp, err := m.httpClient.Get(url)
+	if err != nil {
+		m.Log.Error("Error al consultar a memoria",
+			log.ErrAttr(err),
+			log.StringAttr("url", url),
+		)
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		m.Log.Error("Memoria respondió con error",
+			log.StringAttr("url", url),
+			log.IntAttr("status_code", resp.StatusCode),
+		)
+		return fmt.Errorf("memoria respondió con status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(respuesta)
+}
@@ -1,7 +1,9 @@
 package memoria
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -88,3 +90,119 @@ func TestMemoria_ConsultarEspacio(t *testing.T) {
 		})
 	}
 }
+
+func TestMemoria_MarcosLibres(t *testing.T) {
+	m := NewMemoria("1234", 5678, log.BuildLogger("debug"))
+	httpmock.Activate(t)
+	defer httpmock.DeactivateAndReset()
+
+	tests := []struct {
+		name        string
+		expects     func(m *Memoria)
+		wantLibres  int
+		wantTamanio int
+		wantErr     bool
+	}{
+		{
+			name: "Memoria responde los marcos libres",
+			expects: func(m *Memoria) {
+				httpmock.RegisterResponder(
+					"GET",
+					fmt.Sprintf("http://%s:%d/kernel/marcos-libres", m.IP, m.Puerto),
+					httpmock.NewStringResponder(200, `{"marcos_libres":12,"tamanio_pagina":64}`),
+				)
+			},
+			wantLibres:  12,
+			wantTamanio: 64,
+		},
+		{
+			name: "Memoria responde con error",
+			expects: func(m *Memoria) {
+				httpmock.RegisterResponder(
+					"GET",
+					fmt.Sprintf("http://%s:%d/kernel/marcos-libres", m.IP, m.Puerto),
+					httpmock.NewStringResponder(500, ``),
+				)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expects(m)
+			libres, tamanio, err := m.MarcosLibres()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MarcosLibres() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if libres != tt.wantLibres || tamanio != tt.wantTamanio {
+				t.Errorf("MarcosLibres() = (%d, %d), want (%d, %d)", libres, tamanio, tt.wantLibres, tt.wantTamanio)
+			}
+		})
+	}
+}
+
+func TestMemoria_MetricasProceso(t *testing.T) {
+	m := NewMemoria("1234", 5678, log.BuildLogger("debug"))
+	httpmock.Activate(t)
+	defer httpmock.DeactivateAndReset()
+
+	url := fmt.Sprintf("http://%s:%d/kernel/metricas-proceso?pid=3", m.IP, m.Puerto)
+
+	httpmock.RegisterResponder("GET", url, httpmock.NewStringResponder(200,
+		`{"accesos_tabla_de_paginas":7,"instrucciones_solicitadas":20,"bajadas_a_swap":1,"subidas_a_memoria_principal":1,"lecturas_de_memoria":4,"escrituras_de_memoria":2}`))
+	metricas, err := m.MetricasProceso(3)
+	if err != nil {
+		t.Fatalf("MetricasProceso() error = %v", err)
+	}
+	if metricas.InstruccionesSolicitadas != 20 || metricas.EscriturasDeMemoria != 2 {
+		t.Errorf("MetricasProceso() = %+v", metricas)
+	}
+
+	httpmock.RegisterResponder("GET", url, httpmock.NewStringResponder(404, `Proceso no destruido`))
+	if _, err = m.MetricasProceso(3); err == nil {
+		t.Errorf("MetricasProceso() esperaba error con un proceso no destruido")
+	}
+}
+
+func TestMemoria_EscribirEnProceso(t *testing.T) {
+	m := NewMemoria("1234", 5678, log.BuildLogger("debug"))
+	httpmock.Activate(t)
+	defer httpmock.DeactivateAndReset()
+
+	base := fmt.Sprintf("http://%s:%d", m.IP, m.Puerto)
+	httpmock.RegisterResponder("GET", base+"/cpu/page-size-y-entries", httpmock.NewStringResponder(200,
+		`{"page_size":4,"entries_per_page":4,"number_of_levels":2}`))
+	httpmock.RegisterResponder("GET", base+"/cpu/pagina-a-frame?pid=2&entradas-nivel=0-1",
+		httpmock.NewStringResponder(200, `{"frame":7}`))
+	httpmock.RegisterResponder("GET", base+"/cpu/pagina-a-frame?pid=2&entradas-nivel=0-2",
+		httpmock.NewStringResponder(200, `{"frame":3}`))
+
+	var escrituras []escritura
+	httpmock.RegisterResponder("POST", base+"/cpu/escritura", func(r *http.Request) (*http.Response, error) {
+		var e escritura
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			return nil, err
+		}
+		escrituras = append(escrituras, e)
+		return httpmock.NewStringResponse(200, "OK"), nil
+	})
+
+	// El mensaje empieza al final de la página 1 y sigue en la página 2
+	if err := m.EscribirEnProceso(2, 6, "hola!!"); err != nil {
+		t.Fatalf("EscribirEnProceso() error = %v", err)
+	}
+
+	want := []escritura{
+		{PID: "2", Frame: 7, Offset: 2, ValorAEscribir: "ho"},
+		{PID: "2", Frame: 3, Offset: 0, ValorAEscribir: "la!!"},
+	}
+	if len(escrituras) != len(want) || escrituras[0] != want[0] || escrituras[1] != want[1] {
+		t.Errorf("escrituras = %+v, want %+v", escrituras, want)
+	}
+
+	httpmock.RegisterResponder("GET", base+"/cpu/pagina-a-frame?pid=2&entradas-nivel=0-1",
+		httpmock.NewStringResponder(200, `{"frame":-1}`))
+	if err := m.EscribirEnProceso(2, 6, "hola!!"); err == nil {
+		t.Errorf("EscribirEnProceso() esperaba error con una página sin marco")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -341,7 +342,7 @@ func (h *Handler) PasarProcesoASwap(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	time.Sleep(time.Duration(h.Config.SwapDelay) * time.Millisecond)
+	clock.Sleep(time.Duration(h.Config.SwapDelay) * time.Millisecond)
 	h.PasarProcesoASwapAuxiliar(pid)
 
 	// Devolvemos una respuesta exitosa
@@ -461,7 +462,7 @@ func (h *Handler) SacarProcesoDeSwap(pid string) {
 		)
 		return
 	}
-	time.Sleep(time.Duration(h.Config.SwapDelay) * time.Millisecond)
+	clock.Sleep(time.Duration(h.Config.SwapDelay) * time.Millisecond)
 }
 
 // CompactarSwap Se encarga de compactar tanto la swap como elk bitmap de la swap
@@ -754,6 +755,7 @@ func (h *Handler) ActualizarPaginaCompleta(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	tablaMetricas.CantidadDeEscritura++
+	metricaAccesosMemoria.Inc(operacionEscritura)
 
 	cantAccesosTabla += len(indices)
 	frame, found := buscarMarcoPorPaginaAux(tablaMetricas, indices)
@@ -775,7 +777,7 @@ func (h *Handler) ActualizarPaginaCompleta(w http.ResponseWriter, r *http.Reques
 		pid, frame*h.Config.PageSize, len(data)))
 
 	// Aplicamos el retardo de memoria configurado * cantidad de accesos a tablas
-	time.Sleep(time.Duration(h.Config.MemoryDelay*cantAccesosTabla) * time.Millisecond)
+	clock.Sleep(time.Duration(h.Config.MemoryDelay*cantAccesosTabla) * time.Millisecond)
 
 	// Enviamos una respuesta exitosa
 	w.Header().Set("Content-Type", "application/json")
@@ -808,11 +810,12 @@ func (h *Handler) LeerPagina(w http.ResponseWriter, r *http.Request) {
 
 	tablaMetricas, _ := h.BuscarProcesoPorPID(lectura.PID)
 	tablaMetricas.CantidadDeLectura++
+	metricaAccesosMemoria.Inc(operacionLectura)
 
 	h.Log.Debug("LeerPagina",
 		log.AnyAttr("lecturaMemoria", lecturaMemoria))
 
-	time.Sleep(time.Duration(h.Config.MemoryDelay) * time.Millisecond)
+	clock.Sleep(time.Duration(h.Config.MemoryDelay) * time.Millisecond)
 
 	// Enviamos la respuesta al cliente con el contenido leído
 	w.Header().Set("Content-Type", "application/json")
@@ -853,11 +856,12 @@ func (h *Handler) LeerPaginaCompleta(w http.ResponseWriter, r *http.Request) {
 
 	tablaMetricas, _ := h.BuscarProcesoPorPID(lectura.PID)
 	tablaMetricas.CantidadDeLectura++
+	metricaAccesosMemoria.Inc(operacionLectura)
 
 	h.Log.Debug("LeerPagina",
 		log.AnyAttr("lecturaMemoria", lecturaMemoria))
 
-	time.Sleep(time.Duration(h.Config.MemoryDelay) * time.Millisecond)
+	clock.Sleep(time.Duration(h.Config.MemoryDelay) * time.Millisecond)
 
 	// Enviamos la respuesta al cliente con el contenido leído
 	w.Header().Set("Content-Type", "application/json")
@@ -908,13 +912,14 @@ func (h *Handler) EscribirPagina(w http.ResponseWriter, r *http.Request) {
 
 	tablaMetricas, _ := h.BuscarProcesoPorPID(escritura.PID)
 	tablaMetricas.CantidadDeEscritura++
+	metricaAccesosMemoria.Inc(operacionEscritura)
 
 	/* Log obligatorio: Escritura / lectura en espacio de usuario
 	"## PID: <PID> - <Escritura> - Dir. Física: <DIRECCIÓN_FÍSICA> - Tamaño: <TAMAÑO>"*/
 	h.Log.Info(fmt.Sprintf("## PID: %s - %s - Dir. Física: %d - Tamaño: %d",
 		escritura.PID, escritura.ValorAEscribir, escritura.Frame*h.Config.PageSize+escritura.Offset, len(escritura.ValorAEscribir)))
 
-	time.Sleep(time.Duration(h.Config.MemoryDelay) * time.Millisecond)
+	clock.Sleep(time.Duration(h.Config.MemoryDelay) * time.Millisecond)
 
 	// Devolvemos un status 200 OK
 	w.WriteHeader(http.StatusOK)
@@ -1000,6 +1005,7 @@ func (h *Handler) BuscarMarcoPorPagina(w http.ResponseWriter, r *http.Request) {
 	}
 
 	tablaProceso.CantidadAccesosATablas += len(indices)
+	metricaAccesosTablas.Add(float64(len(indices)))
 	frame, found := buscarMarcoPorPaginaAux(tablaProceso, indices)
 	if !found {
 		h.Log.Error("Error al buscar marco por página",
@@ -1018,7 +1024,7 @@ func (h *Handler) BuscarMarcoPorPagina(w http.ResponseWriter, r *http.Request) {
 	responseBytes, _ := json.Marshal(response)
 
 	// Aplicar retardo de memoria según la cantidad de accesos a tablas (por niveles)
-	time.Sleep(time.Duration(h.Config.MemoryDelay*len(indices)) * time.Millisecond)
+	clock.Sleep(time.Duration(h.Config.MemoryDelay*len(indices)) * time.Millisecond)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -1102,3 +1108,73 @@ func pow(base, exp int) int {
 	}
 	return result
 }
+
+// MarcosLibres Estructura con la que respondemos la consulta de marcos libres
+type MarcosLibres struct {
+	MarcosLibres  int `json:"marcos_libres"`
+	TamanioPagina int `json:"tamanio_pagina"`
+}
+
+// ConsultarMarcosLibres responde cuántos marcos de la memoria de usuario están libres y el tamaño de página, sin
+// reservar nada. El kernel lo usa para elegir qué proceso admitir (best-fit).
+func (h *Handler) ConsultarMarcosLibres(w http.ResponseWriter, r *http.Request) {
+	respuesta := MarcosLibres{
+		MarcosLibres:  h.ContarLibres(),
+		TamanioPagina: h.Config.PageSize,
+	}
+
+	h.Log.DebugContext(r.Context(), "Consulta de marcos libres",
+		log.IntAttr("marcos_libres", respuesta.MarcosLibres),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(respuesta); err != nil {
+		h.Log.Error("Error al codificar la respuesta de marcos libres",
+			log.ErrAttr(err),
+		)
+	}
+}
+
+// CantidadInstrucciones Estructura con la que respondemos la consulta de cantidad de instrucciones
+type CantidadInstrucciones struct {
+	Cantidad int `json:"cantidad"`
+}
+
+// ConsultarCantidadInstrucciones responde cuántas instrucciones tiene un archivo de pseudocódigo, sin cargarlo. El
+// kernel lo usa como estimación de la duración de un proceso antes de admitirlo.
+func (h *Handler) ConsultarCantidadInstrucciones(w http.ResponseWriter, r *http.Request) {
+	filePath := r.URL.Query().Get("archivo")
+	if filePath == "" {
+		h.Log.Error("Archivo de pseudocódigo no proporcionado")
+		http.Error(w, "archivo de pseudocódigo no proporcionado", http.StatusBadRequest)
+		return
+	}
+
+	file, err := os.Open(h.Config.ScriptsPath + filePath)
+	if err != nil {
+		h.Log.Error("Error al abrir el archivo de pseudocodigo",
+			log.ErrAttr(err),
+			log.StringAttr("path-archivo", h.Config.ScriptsPath+filePath),
+		)
+		http.Error(w, "error al abrir el archivo de pseudocodigo", http.StatusNotFound)
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var respuesta CantidadInstrucciones
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			respuesta.Cantidad++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(respuesta); err != nil {
+		h.Log.Error("Error al codificar la respuesta de cantidad de instrucciones",
+			log.ErrAttr(err),
+		)
+	}
+}
@@ -13,11 +13,13 @@ type Config struct {
 	SwapfilePath   string `json:"swapfile_path"`
 	SwapDelay      int    `json:"swap_delay"`
 	LogLevel       string `json:"log_level"`
+	ClockMode      string `json:"clock_mode"`
 	DumpPath       string `json:"dump_path"`
 	ScriptsPath    string `json:"scripts_path"`
 }
 
-// Creo que esto no lo estamos usando
+// MetricasProceso son las métricas de un proceso al momento de destruirlo. Se guardan para que el kernel las sume a su
+// reporte de finalización.
 type MetricasProceso struct {
 	AccesosTablaDePaginas    int `json:"accesos_tabla_de_paginas"`
 	InstruccionesSolicitadas int `json:"instrucciones_solicitadas"`
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"sync"
 
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/config"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
@@ -12,7 +13,8 @@ type Handler struct {
 	Log                    *slog.Logger
 	Config                 *Config
 	EspacioDeUsuario       []byte
-	MetricasProcesos       map[int]*MetricasProceso
+	MetricasProcesos       map[int]*MetricasProceso // Métricas de los procesos destruidos, para el reporte del kernel
+	mutexMetricasProcesos  *sync.RWMutex
 	mutexInstrucciones     *sync.RWMutex
 	Instrucciones          map[int][]Instruccion
 	FrameTable             []bool
@@ -35,7 +37,10 @@ func NewHandler(configFile string) *Handler {
 	// Initialize the logger with the log level from the configuration
 	logLevel := configStruct.LogLevel
 
-	return &Handler{
+	// Initialize the clock (real or virtual) with the mode from the configuration
+	clock.Configurar(configStruct.ClockMode)
+
+	h := &Handler{
 		Config:                 configStruct,
 		Log:                    log.BuildLogger(logLevel),
 		EspacioDeUsuario:       make([]byte, configStruct.MemorySize),
@@ -45,5 +50,9 @@ func NewHandler(configFile string) *Handler {
 		TablasProcesos:         make([]*TablasProceso, 0),
 		ProcesoPorPosicionSwap: make([]int, 0),
 		mutexInstrucciones:     &sync.RWMutex{},
+		mutexMetricasProcesos:  &sync.RWMutex{},
 	}
+	h.registrarMetricas()
+
+	return h
 }
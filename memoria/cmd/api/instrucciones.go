@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
 )
 
@@ -25,7 +26,7 @@ func (h *Handler) RecibirInstruccion(w http.ResponseWriter, r *http.Request) {
 	)
 
 	// Aplicar retardo
-	time.Sleep(time.Duration(h.Config.MemoryDelay) * time.Millisecond)
+	clock.Sleep(time.Duration(h.Config.MemoryDelay) * time.Millisecond)
 
 	h.mutexInstrucciones.RLock()
 	defer h.mutexInstrucciones.RUnlock()
@@ -49,6 +50,17 @@ func (h *Handler) RecibirInstruccion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Un PC fuera del script (por ejemplo, por un GOTO inválido) es un error del proceso, no de memoria
+	if proceso.PC < 0 || proceso.PC >= len(h.Instrucciones[proceso.PID]) {
+		h.Log.Debug("PC fuera del rango de instrucciones del proceso",
+			log.IntAttr("pid", proceso.PID),
+			log.IntAttr("pc", proceso.PC),
+			log.IntAttr("cantidad", len(h.Instrucciones[proceso.PID])),
+		)
+		http.Error(w, "pc out of range", http.StatusNotFound)
+		return
+	}
+
 	instruccion := h.Instrucciones[proceso.PID][proceso.PC]
 
 	/* Log obligatorio: Obtener instrucción
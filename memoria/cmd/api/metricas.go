@@ -0,0 +1,28 @@
+package api
+
+import "github.com/sisoputnfrba/tp-golang/utils/metricas"
+
+const (
+	operacionLectura   = "lectura"
+	operacionEscritura = "escritura"
+)
+
+var (
+	metricaMarcosLibres = metricas.Default.Gauge("memoria_marcos_libres",
+		"Marcos libres de la memoria de usuario")
+	metricaMarcosTotales = metricas.Default.Gauge("memoria_marcos_totales",
+		"Marcos de la memoria de usuario")
+	metricaPaginasEnSwap = metricas.Default.Gauge("memoria_swap_paginas_usadas",
+		"Páginas ocupadas en el archivo de swap")
+	metricaAccesosTablas = metricas.Default.Contador("memoria_accesos_tabla_de_paginas_total",
+		"Accesos a las tablas de páginas, uno por nivel recorrido")
+	metricaAccesosMemoria = metricas.Default.Contador("memoria_accesos_total",
+		"Lecturas y escrituras a la memoria de usuario", "operacion")
+)
+
+// registrarMetricas expone el estado de los marcos y del swap, que se calcula al consultar /metrics
+func (h *Handler) registrarMetricas() {
+	metricaMarcosLibres.SetFunc(func() float64 { return float64(h.ContarLibres()) })
+	metricaMarcosTotales.SetFunc(func() float64 { return float64(len(h.FrameTable)) })
+	metricaPaginasEnSwap.SetFunc(func() float64 { return float64(len(h.ProcesoPorPosicionSwap)) })
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -39,6 +40,7 @@ func (h *Handler) FinalizarProceso(w http.ResponseWriter, r *http.Request) {
 		tablaMetricas.CantidadBajadasSwap, tablaMetricas.CantidadSubidasMemoriaPrincipal,
 		tablaMetricas.CantidadDeLectura, tablaMetricas.CantidadDeEscritura))
 
+	h.guardarMetricasProceso(pid, tablaMetricas)
 	h.finalizarProcesoFuncionAuxiliar(pid)
 
 	// Enviamos una respuesta exitosa
@@ -46,6 +48,55 @@ func (h *Handler) FinalizarProceso(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte("Proceso finalizado correctamente"))
 }
 
+// guardarMetricasProceso conserva las métricas del proceso que se destruye
+func (h *Handler) guardarMetricasProceso(pid string, tabla *TablasProceso) {
+	pidInt, err := strconv.Atoi(pid)
+	if err != nil {
+		return
+	}
+
+	h.mutexMetricasProcesos.Lock()
+	defer h.mutexMetricasProcesos.Unlock()
+
+	h.MetricasProcesos[pidInt] = &MetricasProceso{
+		AccesosTablaDePaginas:    tabla.CantidadAccesosATablas,
+		InstruccionesSolicitadas: tabla.CantidadInstruccionesSolicitadas,
+		BajadasAlSwap:            tabla.CantidadBajadasSwap,
+		SubidasAMemPpal:          tabla.CantidadSubidasMemoriaPrincipal,
+		LecturasDeMemoria:        tabla.CantidadDeLectura,
+		EscriturasDeMemoria:      tabla.CantidadDeEscritura,
+	}
+}
+
+// ConsultarMetricasProceso devuelve las métricas con las que se destruyó el proceso ?pid=
+func (h *Handler) ConsultarMetricasProceso(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Error(w, "PID inválido", http.StatusBadRequest)
+		return
+	}
+
+	h.mutexMetricasProcesos.RLock()
+	metricas, ok := h.MetricasProcesos[pid]
+	h.mutexMetricasProcesos.RUnlock()
+
+	if !ok {
+		http.Error(w, "Proceso no destruido", http.StatusNotFound)
+		return
+	}
+
+	body, err := json.Marshal(metricas)
+	if err != nil {
+		h.Log.Error("Error al serializar las métricas del proceso", log.ErrAttr(err))
+		http.Error(w, "Error interno del servidor", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
 func (h *Handler) finalizarProcesoFuncionAuxiliar(pid string) {
 	pidInt, _ := strconv.Atoi(pid)
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/sisoputnfrba/tp-golang/memoria/cmd/api"
 	"github.com/sisoputnfrba/tp-golang/utils/log"
+	"github.com/sisoputnfrba/tp-golang/utils/metricas"
 )
 
 const (
@@ -30,12 +31,16 @@ func main() {
 	mux.HandleFunc("POST /cpu/lectura-completa", h.LeerPaginaCompleta)                     // CPU --> Memoria
 	mux.HandleFunc("GET /cpu/pagina-a-frame", h.BuscarMarcoPorPagina)                      // CPU --> Memoria
 	mux.HandleFunc("GET /kernel/espacio-disponible", h.ConsultarEspacioEInicializar)       // Kernel --> Memoria
+	mux.HandleFunc("GET /kernel/marcos-libres", h.ConsultarMarcosLibres)                   // Kernel --> Memoria
+	mux.HandleFunc("GET /kernel/cantidad-instrucciones", h.ConsultarCantidadInstrucciones) // Kernel --> Memoria
 	mux.HandleFunc("/kernel/cargar-memoria-de-sistema", h.CargarProcesoEnMemoriaDeSistema) // Kernel --> Memoria
 	mux.HandleFunc("GET /kernel/swap-proceso", h.PasarProcesoASwap)                        // Kernel --> Memoria
 	mux.HandleFunc("/kernel/dump-proceso", h.DumpProceso)                                  // Kernel --> Memoria
 	mux.HandleFunc("POST /kernel/fin-proceso", h.FinalizarProceso)                         // Kernel --> Memoria
+	mux.HandleFunc("GET /kernel/metricas-proceso", h.ConsultarMetricasProceso)             // Kernel --> Memoria
 	mux.HandleFunc("GET /cpu/page-size-y-entries", h.RetornarPageSizeYEntries)             // CPU --> Memoria
 	mux.HandleFunc("POST /cpu/actualizar-pag-completa", h.ActualizarPaginaCompleta)        // CPU --> Memoria
+	mux.Handle("GET /metrics", metricas.Handler())
 
 	memoriaAddress := fmt.Sprintf("%s:%d", h.Config.IpMemory, h.Config.PortMemory)
 	if err := http.ListenAndServe(memoriaAddress, mux); err != nil {
@@ -0,0 +1,200 @@
+package clock
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ModoReal    = "real"    // El tiempo es el del sistema operativo
+	ModoVirtual = "virtual" // El tiempo avanza lógicamente, solo cuando alguien duerme o lo adelanta
+)
+
+// Clock es la fuente de tiempo que usan todos los módulos para sus decisiones de planificación, retardos y métricas.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+	// AvanzarHasta adelanta el reloj hasta el instante dado. En el reloj real no tiene efecto.
+	AvanzarHasta(t time.Time)
+}
+
+// Timer es un temporizador creado con AfterFunc
+type Timer interface {
+	// Stop cancela el temporizador. Devuelve false si ya había vencido o si ya estaba cancelado.
+	Stop() bool
+}
+
+var (
+	actual      Clock = Real{}
+	mutexActual sync.RWMutex
+)
+
+// Configurar elige el reloj del módulo según el modo de la configuración (clock_mode). Un modo vacío o desconocido
+// usa el reloj real.
+func Configurar(modo string) {
+	if strings.EqualFold(modo, ModoVirtual) {
+		Usar(NewVirtual(time.Unix(0, 0)))
+		return
+	}
+
+	Usar(Real{})
+}
+
+// Usar reemplaza el reloj del módulo. Sirve principalmente para los tests.
+func Usar(c Clock) {
+	mutexActual.Lock()
+	defer mutexActual.Unlock()
+
+	actual = c
+}
+
+// Actual devuelve el reloj del módulo
+func Actual() Clock {
+	mutexActual.RLock()
+	defer mutexActual.RUnlock()
+
+	return actual
+}
+
+// EsVirtual indica si el módulo usa el reloj virtual
+func EsVirtual() bool {
+	_, ok := Actual().(*Virtual)
+	return ok
+}
+
+func Now() time.Time                            { return Actual().Now() }
+func Since(t time.Time) time.Duration           { return Actual().Since(t) }
+func Sleep(d time.Duration)                     { Actual().Sleep(d) }
+func After(d time.Duration) <-chan time.Time    { return Actual().After(d) }
+func AfterFunc(d time.Duration, f func()) Timer { return Actual().AfterFunc(d, f) }
+func AvanzarHasta(t time.Time)                  { Actual().AvanzarHasta(t) }
+
+// Real usa el reloj del sistema operativo
+type Real struct{}
+
+func (Real) Now() time.Time                            { return time.Now() }
+func (Real) Since(t time.Time) time.Duration           { return time.Since(t) }
+func (Real) Sleep(d time.Duration)                     { time.Sleep(d) }
+func (Real) After(d time.Duration) <-chan time.Time    { return time.After(d) }
+func (Real) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+func (Real) AvanzarHasta(time.Time)                    {}
+
+// Virtual es un reloj lógico: el tiempo no pasa solo, sino que avanza cuando algún componente duerme (Sleep) o
+// cuando se lo adelanta explícitamente (AvanzarHasta). Así, con las mismas entradas se obtienen las mismas
+// transiciones y métricas, y las esperas largas (por ejemplo, una IO de 20 segundos) se resuelven al instante.
+// Los temporizadores (After, AfterFunc) vencen en orden cuando el reloj alcanza su instante; las funciones de
+// AfterFunc se ejecutan en la goroutine que adelantó el reloj.
+type Virtual struct {
+	mutex       sync.Mutex
+	ahora       time.Time
+	pendientes  []*timerVirtual
+	secuenciaID int
+}
+
+type timerVirtual struct {
+	reloj    *Virtual
+	id       int
+	vence    time.Time
+	disparar func()
+}
+
+// NewVirtual crea un reloj virtual que comienza en el instante dado
+func NewVirtual(inicio time.Time) *Virtual {
+	return &Virtual{ahora: inicio}
+}
+
+func (v *Virtual) Now() time.Time {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	return v.ahora
+}
+
+func (v *Virtual) Since(t time.Time) time.Duration {
+	return v.Now().Sub(t)
+}
+
+// Sleep no bloquea: adelanta el reloj la duración pedida y vence los temporizadores alcanzados
+func (v *Virtual) Sleep(d time.Duration) {
+	v.AvanzarHasta(v.Now().Add(d))
+}
+
+func (v *Virtual) After(d time.Duration) <-chan time.Time {
+	canal := make(chan time.Time, 1)
+	v.AfterFunc(d, func() {
+		canal <- v.Now()
+	})
+
+	return canal
+}
+
+func (v *Virtual) AfterFunc(d time.Duration, f func()) Timer {
+	v.mutex.Lock()
+	v.secuenciaID++
+	t := &timerVirtual{
+		reloj:    v,
+		id:       v.secuenciaID,
+		vence:    v.ahora.Add(d),
+		disparar: f,
+	}
+	v.pendientes = append(v.pendientes, t)
+	v.mutex.Unlock()
+
+	// Un temporizador sin duración vence inmediatamente
+	if d <= 0 {
+		v.AvanzarHasta(v.Now())
+	}
+
+	return t
+}
+
+// AvanzarHasta adelanta el reloj hasta el instante dado, venciendo en orden los temporizadores alcanzados. Si el
+// instante ya pasó, el reloj no retrocede.
+func (v *Virtual) AvanzarHasta(t time.Time) {
+	for {
+		v.mutex.Lock()
+		sort.SliceStable(v.pendientes, func(i, j int) bool {
+			if v.pendientes[i].vence.Equal(v.pendientes[j].vence) {
+				return v.pendientes[i].id < v.pendientes[j].id
+			}
+			return v.pendientes[i].vence.Before(v.pendientes[j].vence)
+		})
+
+		if len(v.pendientes) == 0 || v.pendientes[0].vence.After(t) {
+			if t.After(v.ahora) {
+				v.ahora = t
+			}
+			v.mutex.Unlock()
+			return
+		}
+
+		// Se vence el próximo temporizador, fuera del lock para que pueda usar el reloj
+		proximo := v.pendientes[0]
+		v.pendientes = v.pendientes[1:]
+		if proximo.vence.After(v.ahora) {
+			v.ahora = proximo.vence
+		}
+		v.mutex.Unlock()
+
+		proximo.disparar()
+	}
+}
+
+func (t *timerVirtual) Stop() bool {
+	t.reloj.mutex.Lock()
+	defer t.reloj.mutex.Unlock()
+
+	for i, pendiente := range t.reloj.pendientes {
+		if pendiente == t {
+			t.reloj.pendientes = append(t.reloj.pendientes[:i], t.reloj.pendientes[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,67 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVirtual_SleepAdelantaSinBloquear(t *testing.T) {
+	inicio := time.Unix(0, 0)
+	v := NewVirtual(inicio)
+
+	antes := time.Now()
+	v.Sleep(20 * time.Second) // IO DISCO 20000
+	if time.Since(antes) > time.Second {
+		t.Fatalf("Sleep virtual bloqueó %v", time.Since(antes))
+	}
+
+	if got := v.Since(inicio); got != 20*time.Second {
+		t.Fatalf("Since = %v, se esperaba 20s", got)
+	}
+}
+
+func TestVirtual_TemporizadoresVencenEnOrden(t *testing.T) {
+	v := NewVirtual(time.Unix(0, 0))
+
+	var orden []int
+	v.AfterFunc(3*time.Second, func() { orden = append(orden, 3) })
+	v.AfterFunc(1*time.Second, func() { orden = append(orden, 1) })
+	cancelado := v.AfterFunc(2*time.Second, func() { orden = append(orden, 2) })
+
+	if !cancelado.Stop() {
+		t.Fatal("Stop debería cancelar un temporizador pendiente")
+	}
+
+	v.AvanzarHasta(time.Unix(2, 0))
+	if len(orden) != 1 || orden[0] != 1 {
+		t.Fatalf("orden = %v, se esperaba [1]", orden)
+	}
+
+	v.Sleep(5 * time.Second)
+	if len(orden) != 2 || orden[1] != 3 {
+		t.Fatalf("orden = %v, se esperaba [1 3]", orden)
+	}
+
+	if got := v.Now(); !got.Equal(time.Unix(7, 0)) {
+		t.Fatalf("Now = %v, se esperaba %v", got, time.Unix(7, 0))
+	}
+}
+
+func TestVirtual_AfterEsperaAlAvance(t *testing.T) {
+	v := NewVirtual(time.Unix(0, 0))
+	canal := v.After(time.Second)
+
+	select {
+	case <-canal:
+		t.Fatal("After venció sin que avance el reloj")
+	default:
+	}
+
+	v.Sleep(time.Second)
+
+	select {
+	case <-canal:
+	default:
+		t.Fatal("After no venció al avanzar el reloj")
+	}
+}
@@ -0,0 +1,79 @@
+package contexto
+
+// Motivo es la razón por la que la CPU devuelve un proceso al kernel al terminar una ráfaga
+type Motivo string
+
+const (
+	MotivoExit                Motivo = "EXIT"                // El proceso ejecutó EXIT
+	MotivoSyscallBloqueante   Motivo = "SYSCALL_BLOCKING"    // El proceso ejecutó una syscall bloqueante (IO, DUMP_MEMORY, WAIT)
+	MotivoDesalojo            Motivo = "PREEMPTED"           // El proceso fue desalojado por una interrupción
+	MotivoSyscallFallida      Motivo = "SYSCALL_FAILED"      // La syscall no llegó al kernel, se reintenta al volver a ejecutar
+	MotivoErrorFetch          Motivo = "FETCH_ERROR"         // No se pudo obtener la instrucción de memoria
+	MotivoInstruccionInvalida Motivo = "INVALID_INSTRUCTION" // La instrucción no existe o sus argumentos son inválidos
+	MotivoFalloMemoria        Motivo = "MEMORY_FAULT"        // Falló un acceso a memoria de READ o WRITE
+)
+
+// Valido indica si el motivo es uno de los conocidos
+func (m Motivo) Valido() bool {
+	switch m {
+	case MotivoExit, MotivoSyscallBloqueante, MotivoDesalojo, MotivoSyscallFallida,
+		MotivoErrorFetch, MotivoInstruccionInvalida, MotivoFalloMemoria:
+		return true
+	}
+
+	return false
+}
+
+// EsError indica si el proceso terminó la ráfaga por un error de ejecución
+func (m Motivo) EsError() bool {
+	switch m {
+	case MotivoErrorFetch, MotivoInstruccionInvalida, MotivoFalloMemoria:
+		return true
+	}
+
+	return false
+}
+
+// Códigos de las excepciones que levanta la CPU, que el kernel usa como motivo de finalización del proceso
+const (
+	CodigoSegmentationFault   = "SEGMENTATION_FAULT" // Acceso a una dirección lógica fuera del proceso
+	CodigoInstruccionInvalida = "INVALID_OPCODE"     // La instrucción no existe
+	CodigoCantidadArgumentos  = "WRONG_ARITY"        // La instrucción tiene de más o de menos argumentos
+	CodigoArgumentoInvalido   = "INVALID_ARGUMENT"   // Un argumento numérico (dirección, tamaño, PC, tiempo) no lo es
+	CodigoPCFueraDeRango      = "PC_OUT_OF_RANGE"    // El PC quedó fuera del script del proceso
+	CodigoSyscallRechazada    = "SYSCALL_REJECTED"   // El kernel rechazó la syscall (argumentos o recurso inválidos)
+)
+
+// Contexto es el contexto de ejecución que la CPU devuelve al kernel al terminar una ráfaga
+type Contexto struct {
+	PID     int      `json:"pid"`
+	PC      int      `json:"pc"`
+	Motivo  Motivo   `json:"motivo"`
+	Syscall *Syscall `json:"syscall,omitempty"` // Solo con MotivoSyscallBloqueante
+	Error   *Error   `json:"error,omitempty"`   // Solo con los motivos de error
+	Rafaga  int      `json:"rafaga,omitempty"`  // Milisegundos que duró la ráfaga según el reloj de la CPU
+}
+
+// Avance es lo que lleva ejecutando la ráfaga en curso. Con el reloj virtual la CPU lo informa después de cada
+// instrucción, para que el reloj del kernel avance mientras el proceso ejecuta y venzan el quantum y los demás
+// temporizadores.
+type Avance struct {
+	PID    int `json:"pid"`
+	Rafaga int `json:"rafaga"` // Milisegundos desde el inicio de la ráfaga según el reloj de la CPU
+}
+
+// Syscall es la syscall bloqueante que terminó la ráfaga
+type Syscall struct {
+	Instruccion string   `json:"instruccion"`
+	Args        []string `json:"args,omitempty"`
+	Reintento   bool     `json:"reintento,omitempty"` // El kernel ya la recibió por /cpu/proceso y no pudo atenderla sin bloquear
+}
+
+// Error es el detalle del error que terminó la ráfaga
+type Error struct {
+	Codigo      string   `json:"codigo,omitempty"` // Código del error, por ejemplo CodigoSegmentationFault
+	Mensaje     string   `json:"mensaje"`
+	Instruccion string   `json:"instruccion,omitempty"` // Instrucción que falló, si se llegó a obtener
+	Args        []string `json:"args,omitempty"`
+	Direccion   string   `json:"direccion,omitempty"` // Dirección lógica del acceso, en los fallos de memoria
+}
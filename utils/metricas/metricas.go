@@ -0,0 +1,220 @@
+package metricas
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	tipoContador = "counter"
+	tipoGauge    = "gauge"
+
+	// ContentType es el tipo de contenido del formato de texto de Prometheus
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// Registro guarda las métricas de un módulo y las expone en el formato de texto de Prometheus. Cada métrica tiene un
+// nombre, una descripción y opcionalmente etiquetas; cada combinación de valores de etiquetas es una serie distinta.
+type Registro struct {
+	mutex    sync.RWMutex
+	familias map[string]*familia
+}
+
+// Default es el registro que usan los módulos, y el que expone Handler
+var Default = NewRegistro()
+
+func NewRegistro() *Registro {
+	return &Registro{familias: make(map[string]*familia)}
+}
+
+// familia es una métrica con todas sus series
+type familia struct {
+	nombre    string
+	ayuda     string
+	tipo      string
+	etiquetas []string
+	mutex     sync.Mutex
+	series    map[string]*serie // Por clave de valores de etiquetas
+}
+
+// serie es el valor de una combinación de valores de etiquetas. Si tiene funcion, el valor se calcula al exponerla.
+type serie struct {
+	valores []string
+	valor   float64
+	funcion func() float64
+}
+
+// Contador es una métrica que solo aumenta, por ejemplo la cantidad de instrucciones ejecutadas
+type Contador struct {
+	familia *familia
+}
+
+// Gauge es una métrica que puede subir y bajar, por ejemplo la cantidad de procesos en una cola
+type Gauge struct {
+	familia *familia
+}
+
+// Contador devuelve el contador con el nombre dado, creándolo si no existe
+func (r *Registro) Contador(nombre, ayuda string, etiquetas ...string) *Contador {
+	return &Contador{familia: r.familia(nombre, ayuda, tipoContador, etiquetas)}
+}
+
+// Gauge devuelve el gauge con el nombre dado, creándolo si no existe
+func (r *Registro) Gauge(nombre, ayuda string, etiquetas ...string) *Gauge {
+	return &Gauge{familia: r.familia(nombre, ayuda, tipoGauge, etiquetas)}
+}
+
+func (r *Registro) familia(nombre, ayuda, tipo string, etiquetas []string) *familia {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if f, ok := r.familias[nombre]; ok {
+		if f.tipo != tipo || len(f.etiquetas) != len(etiquetas) {
+			panic(fmt.Sprintf("metricas: la métrica %s ya está registrada con otro tipo o etiquetas", nombre))
+		}
+		return f
+	}
+
+	f := &familia{
+		nombre:    nombre,
+		ayuda:     ayuda,
+		tipo:      tipo,
+		etiquetas: etiquetas,
+		series:    make(map[string]*serie),
+	}
+	r.familias[nombre] = f
+	return f
+}
+
+// Inc suma uno a la serie con los valores de etiquetas dados
+func (c *Contador) Inc(valores ...string) {
+	c.Add(1, valores...)
+}
+
+// Add suma delta a la serie con los valores de etiquetas dados. Los valores negativos se ignoran.
+func (c *Contador) Add(delta float64, valores ...string) {
+	if delta < 0 {
+		return
+	}
+
+	c.familia.modificar(valores, func(s *serie) { s.valor += delta })
+}
+
+// Set fija el valor de la serie con los valores de etiquetas dados
+func (g *Gauge) Set(valor float64, valores ...string) {
+	g.familia.modificar(valores, func(s *serie) { s.valor = valor })
+}
+
+// Add suma delta (que puede ser negativo) a la serie con los valores de etiquetas dados
+func (g *Gauge) Add(delta float64, valores ...string) {
+	g.familia.modificar(valores, func(s *serie) { s.valor += delta })
+}
+
+// SetFunc hace que el valor de la serie se calcule con funcion cada vez que se exponen las métricas. Sirve para los
+// valores que el módulo ya conoce, como el largo de una cola o los marcos libres.
+func (g *Gauge) SetFunc(funcion func() float64, valores ...string) {
+	g.familia.modificar(valores, func(s *serie) { s.funcion = funcion })
+}
+
+func (f *familia) modificar(valores []string, modificar func(s *serie)) {
+	if len(valores) != len(f.etiquetas) {
+		panic(fmt.Sprintf("metricas: la métrica %s espera %d etiquetas y recibió %d", f.nombre, len(f.etiquetas), len(valores)))
+	}
+
+	clave := strings.Join(valores, "\xff")
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	s, ok := f.series[clave]
+	if !ok {
+		s = &serie{valores: append([]string(nil), valores...)}
+		f.series[clave] = s
+	}
+	modificar(s)
+}
+
+func (s *serie) leer() float64 {
+	if s.funcion != nil {
+		return s.funcion()
+	}
+	return s.valor
+}
+
+// Exponer devuelve todas las métricas en el formato de texto de Prometheus, ordenadas por nombre y por valores de
+// etiquetas
+func (r *Registro) Exponer() string {
+	r.mutex.RLock()
+	familias := make([]*familia, 0, len(r.familias))
+	for _, f := range r.familias {
+		familias = append(familias, f)
+	}
+	r.mutex.RUnlock()
+
+	sort.Slice(familias, func(i, j int) bool { return familias[i].nombre < familias[j].nombre })
+
+	var sb strings.Builder
+	for _, f := range familias {
+		f.exponer(&sb)
+	}
+
+	return sb.String()
+}
+
+func (f *familia) exponer(sb *strings.Builder) {
+	// Se copian las series para no calcular las funciones con el mutex bloqueado
+	f.mutex.Lock()
+	series := make([]serie, 0, len(f.series))
+	for _, s := range f.series {
+		series = append(series, *s)
+	}
+	f.mutex.Unlock()
+
+	sort.Slice(series, func(i, j int) bool {
+		return strings.Join(series[i].valores, "\xff") < strings.Join(series[j].valores, "\xff")
+	})
+
+	sb.WriteString(fmt.Sprintf("# HELP %s %s\n", f.nombre, escaparAyuda(f.ayuda)))
+	sb.WriteString(fmt.Sprintf("# TYPE %s %s\n", f.nombre, f.tipo))
+
+	// Las métricas sin etiquetas se exponen en 0 aunque todavía no se hayan modificado
+	if len(series) == 0 && len(f.etiquetas) == 0 {
+		series = append(series, serie{})
+	}
+
+	for _, s := range series {
+		sb.WriteString(f.nombre)
+		if len(f.etiquetas) > 0 {
+			pares := make([]string, len(f.etiquetas))
+			for i, etiqueta := range f.etiquetas {
+				pares[i] = fmt.Sprintf(`%s="%s"`, etiqueta, escaparValor(s.valores[i]))
+			}
+			sb.WriteString("{" + strings.Join(pares, ",") + "}")
+		}
+		sb.WriteString(" " + strconv.FormatFloat(s.leer(), 'g', -1, 64) + "\n")
+	}
+}
+
+// ServeHTTP expone las métricas del registro, para registrarlo en el mux como GET /metrics
+func (r *Registro) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(r.Exponer()))
+}
+
+// Handler devuelve el handler de GET /metrics del registro Default
+func Handler() http.Handler {
+	return Default
+}
+
+func escaparAyuda(ayuda string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(ayuda)
+}
+
+func escaparValor(valor string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(valor)
+}
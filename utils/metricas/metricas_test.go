@@ -0,0 +1,58 @@
+package metricas
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistro_Exponer(t *testing.T) {
+	r := NewRegistro()
+
+	transiciones := r.Contador("kernel_transiciones_total", "Cambios de estado de los procesos", "desde", "hacia")
+	transiciones.Inc("NEW", "READY")
+	transiciones.Inc("NEW", "READY")
+	transiciones.Inc("READY", "EXEC")
+
+	cola := 0
+	r.Gauge("kernel_procesos_en_cola", "Procesos en cada cola", "estado").SetFunc(func() float64 {
+		return float64(cola)
+	}, "READY")
+	cola = 3
+
+	r.Contador("cpu_instrucciones_total", "Instrucciones ejecutadas")
+
+	want := `# HELP cpu_instrucciones_total Instrucciones ejecutadas
+# TYPE cpu_instrucciones_total counter
+cpu_instrucciones_total 0
+# HELP kernel_procesos_en_cola Procesos en cada cola
+# TYPE kernel_procesos_en_cola gauge
+kernel_procesos_en_cola{estado="READY"} 3
+# HELP kernel_transiciones_total Cambios de estado de los procesos
+# TYPE kernel_transiciones_total counter
+kernel_transiciones_total{desde="NEW",hacia="READY"} 2
+kernel_transiciones_total{desde="READY",hacia="EXEC"} 1
+`
+	if got := r.Exponer(); got != want {
+		t.Fatalf("Exponer() =\n%s\nse esperaba\n%s", got, want)
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if rec.Body.String() != want {
+		t.Errorf("ServeHTTP devolvió otro contenido que Exponer")
+	}
+}
+
+func TestRegistro_EscapaValores(t *testing.T) {
+	r := NewRegistro()
+	r.Gauge("io_ocupado", "Dispositivo \\ ocupado", "nombre").Set(1, `DISCO "A"`)
+
+	if got := r.Exponer(); !strings.Contains(got, `io_ocupado{nombre="DISCO \"A\""} 1`) ||
+		!strings.Contains(got, `# HELP io_ocupado Dispositivo \\ ocupado`) {
+		t.Fatalf("valores sin escapar:\n%s", got)
+	}
+}